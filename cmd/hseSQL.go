@@ -1,18 +1,120 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
+	"hseSQL/internal/database"
 	"hseSQL/internal/runner"
+	"hseSQL/internal/seed"
+	"io"
 	"log"
+	"os"
 )
 
+const usage = `usage: hseSQL [command]
+
+commands:
+  serve           run the server, the default
+  export [file]   write the dump of the catalog to the file or to stdout
+  import [file]   restore the catalog from the dump in the file or in stdin
+  seed [file]     apply the seed file, configs/seed.yaml by default`
+
 func main() {
 	c, err := runner.ReadConfig("configs/config.yaml")
 	if err != nil {
 		log.Fatal(err)
 	}
-	r, err := runner.NewRunner(c)
+	command := "serve"
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+	var file string
+	if len(os.Args) > 2 {
+		file = os.Args[2]
+	}
+	switch command {
+	case "serve":
+		r, err := runner.NewRunner(c)
+		if err != nil {
+			log.Fatal(err)
+		}
+		r.Run()
+	case "export":
+		err = export(c, file)
+	case "import":
+		err = restore(c, file)
+	case "seed":
+		err = applySeed(c, file)
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
 	if err != nil {
 		log.Fatal(err)
 	}
-	r.Run()
+}
+
+func export(c *runner.Config, file string) error {
+	do, err := runner.OpenDb(c)
+	if err != nil {
+		return err
+	}
+	d, err := do.DumpCatalog()
+	if err != nil {
+		return err
+	}
+	var w io.Writer = os.Stdout
+	if file != "" {
+		f, err := os.Create(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(d)
+}
+
+func restore(c *runner.Config, file string) error {
+	var r io.Reader = os.Stdin
+	if file != "" {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	d, err := database.DecodeDump(r)
+	if err != nil {
+		return err
+	}
+	return restoreDump(c, d)
+}
+
+func applySeed(c *runner.Config, file string) error {
+	if file == "" {
+		file = "configs/seed.yaml"
+	}
+	f, err := seed.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	return restoreDump(c, f.Dump())
+}
+
+func restoreDump(c *runner.Config, d *database.Dump) error {
+	do, err := runner.OpenDb(c)
+	if err != nil {
+		return err
+	}
+	report, err := do.RestoreCatalog(d)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
 }
@@ -1,11 +1,16 @@
 module hseSQL
 
-go 1.13
+go 1.16
 
 require (
 	github.com/go-chi/chi v4.0.3+incompatible
+	github.com/golang/protobuf v1.3.5
+	github.com/graph-gophers/graphql-go v1.3.0
+	github.com/jackc/pgconn v1.3.2
 	github.com/jackc/pgx/v4 v4.4.1
 	github.com/olekukonko/tablewriter v0.0.4
 	github.com/sirupsen/logrus v1.4.2
+	github.com/swaggo/files/v2 v2.0.2
+	google.golang.org/grpc v1.29.1
 	gopkg.in/yaml.v2 v2.2.2
 )
@@ -0,0 +1,2229 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: catalog.proto
+
+package catalogpb
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	wrappers "github.com/golang/protobuf/ptypes/wrappers"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type Empty struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Empty) Reset()         { *m = Empty{} }
+func (m *Empty) String() string { return proto.CompactTextString(m) }
+func (*Empty) ProtoMessage()    {}
+func (*Empty) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{0}
+}
+
+func (m *Empty) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Empty.Unmarshal(m, b)
+}
+func (m *Empty) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Empty.Marshal(b, m, deterministic)
+}
+func (m *Empty) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Empty.Merge(m, src)
+}
+func (m *Empty) XXX_Size() int {
+	return xxx_messageInfo_Empty.Size(m)
+}
+func (m *Empty) XXX_DiscardUnknown() {
+	xxx_messageInfo_Empty.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Empty proto.InternalMessageInfo
+
+type Page struct {
+	// zero limit means the rest of the list
+	Limit  int32 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset int32 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	// sort key prefixed with - for the descending order
+	Sort                 string   `protobuf:"bytes,3,opt,name=sort,proto3" json:"sort,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Page) Reset()         { *m = Page{} }
+func (m *Page) String() string { return proto.CompactTextString(m) }
+func (*Page) ProtoMessage()    {}
+func (*Page) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{1}
+}
+
+func (m *Page) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Page.Unmarshal(m, b)
+}
+func (m *Page) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Page.Marshal(b, m, deterministic)
+}
+func (m *Page) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Page.Merge(m, src)
+}
+func (m *Page) XXX_Size() int {
+	return xxx_messageInfo_Page.Size(m)
+}
+func (m *Page) XXX_DiscardUnknown() {
+	xxx_messageInfo_Page.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Page proto.InternalMessageInfo
+
+func (m *Page) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *Page) GetOffset() int32 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *Page) GetSort() string {
+	if m != nil {
+		return m.Sort
+	}
+	return ""
+}
+
+// EI is a unit of measure: base = value * factor + offset
+type EI struct {
+	Id                   int32    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	ShortName            string   `protobuf:"bytes,3,opt,name=short_name,json=shortName,proto3" json:"short_name,omitempty"`
+	Dimension            string   `protobuf:"bytes,4,opt,name=dimension,proto3" json:"dimension,omitempty"`
+	Factor               float64  `protobuf:"fixed64,5,opt,name=factor,proto3" json:"factor,omitempty"`
+	Offset               float64  `protobuf:"fixed64,6,opt,name=offset,proto3" json:"offset,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EI) Reset()         { *m = EI{} }
+func (m *EI) String() string { return proto.CompactTextString(m) }
+func (*EI) ProtoMessage()    {}
+func (*EI) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{2}
+}
+
+func (m *EI) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EI.Unmarshal(m, b)
+}
+func (m *EI) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EI.Marshal(b, m, deterministic)
+}
+func (m *EI) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EI.Merge(m, src)
+}
+func (m *EI) XXX_Size() int {
+	return xxx_messageInfo_EI.Size(m)
+}
+func (m *EI) XXX_DiscardUnknown() {
+	xxx_messageInfo_EI.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EI proto.InternalMessageInfo
+
+func (m *EI) GetId() int32 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *EI) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *EI) GetShortName() string {
+	if m != nil {
+		return m.ShortName
+	}
+	return ""
+}
+
+func (m *EI) GetDimension() string {
+	if m != nil {
+		return m.Dimension
+	}
+	return ""
+}
+
+func (m *EI) GetFactor() float64 {
+	if m != nil {
+		return m.Factor
+	}
+	return 0
+}
+
+func (m *EI) GetOffset() float64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+type EnumValue struct {
+	Id                   int32    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Value                string   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Retired              bool     `protobuf:"varint,3,opt,name=retired,proto3" json:"retired,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EnumValue) Reset()         { *m = EnumValue{} }
+func (m *EnumValue) String() string { return proto.CompactTextString(m) }
+func (*EnumValue) ProtoMessage()    {}
+func (*EnumValue) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{3}
+}
+
+func (m *EnumValue) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EnumValue.Unmarshal(m, b)
+}
+func (m *EnumValue) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EnumValue.Marshal(b, m, deterministic)
+}
+func (m *EnumValue) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EnumValue.Merge(m, src)
+}
+func (m *EnumValue) XXX_Size() int {
+	return xxx_messageInfo_EnumValue.Size(m)
+}
+func (m *EnumValue) XXX_DiscardUnknown() {
+	xxx_messageInfo_EnumValue.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EnumValue proto.InternalMessageInfo
+
+func (m *EnumValue) GetId() int32 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *EnumValue) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+func (m *EnumValue) GetRetired() bool {
+	if m != nil {
+		return m.Retired
+	}
+	return false
+}
+
+type ValueType struct {
+	Id                   int32        `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string       `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	BaseType             string       `protobuf:"bytes,3,opt,name=base_type,json=baseType,proto3" json:"base_type,omitempty"`
+	Values               []*EnumValue `protobuf:"bytes,4,rep,name=values,proto3" json:"values,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ValueType) Reset()         { *m = ValueType{} }
+func (m *ValueType) String() string { return proto.CompactTextString(m) }
+func (*ValueType) ProtoMessage()    {}
+func (*ValueType) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{4}
+}
+
+func (m *ValueType) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ValueType.Unmarshal(m, b)
+}
+func (m *ValueType) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ValueType.Marshal(b, m, deterministic)
+}
+func (m *ValueType) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ValueType.Merge(m, src)
+}
+func (m *ValueType) XXX_Size() int {
+	return xxx_messageInfo_ValueType.Size(m)
+}
+func (m *ValueType) XXX_DiscardUnknown() {
+	xxx_messageInfo_ValueType.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ValueType proto.InternalMessageInfo
+
+func (m *ValueType) GetId() int32 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *ValueType) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ValueType) GetBaseType() string {
+	if m != nil {
+		return m.BaseType
+	}
+	return ""
+}
+
+func (m *ValueType) GetValues() []*EnumValue {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+// Value is a param value, dates are YYYY-MM-DD strings and enum values are their text
+type Value struct {
+	// Types that are valid to be assigned to Kind:
+	//	*Value_IntegerValue
+	//	*Value_DecimalValue
+	//	*Value_BooleanValue
+	//	*Value_StringValue
+	//	*Value_DecimalText
+	Kind                 isValue_Kind `protobuf_oneof:"kind"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *Value) Reset()         { *m = Value{} }
+func (m *Value) String() string { return proto.CompactTextString(m) }
+func (*Value) ProtoMessage()    {}
+func (*Value) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{5}
+}
+
+func (m *Value) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Value.Unmarshal(m, b)
+}
+func (m *Value) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Value.Marshal(b, m, deterministic)
+}
+func (m *Value) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Value.Merge(m, src)
+}
+func (m *Value) XXX_Size() int {
+	return xxx_messageInfo_Value.Size(m)
+}
+func (m *Value) XXX_DiscardUnknown() {
+	xxx_messageInfo_Value.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Value proto.InternalMessageInfo
+
+type isValue_Kind interface {
+	isValue_Kind()
+}
+
+type Value_IntegerValue struct {
+	IntegerValue int64 `protobuf:"varint,1,opt,name=integer_value,json=integerValue,proto3,oneof"`
+}
+
+type Value_DecimalValue struct {
+	DecimalValue float64 `protobuf:"fixed64,2,opt,name=decimal_value,json=decimalValue,proto3,oneof"`
+}
+
+type Value_BooleanValue struct {
+	BooleanValue bool `protobuf:"varint,3,opt,name=boolean_value,json=booleanValue,proto3,oneof"`
+}
+
+type Value_StringValue struct {
+	StringValue string `protobuf:"bytes,4,opt,name=string_value,json=stringValue,proto3,oneof"`
+}
+
+type Value_DecimalText struct {
+	DecimalText string `protobuf:"bytes,5,opt,name=decimal_text,json=decimalText,proto3,oneof"`
+}
+
+func (*Value_IntegerValue) isValue_Kind() {}
+
+func (*Value_DecimalValue) isValue_Kind() {}
+
+func (*Value_BooleanValue) isValue_Kind() {}
+
+func (*Value_StringValue) isValue_Kind() {}
+
+func (*Value_DecimalText) isValue_Kind() {}
+
+func (m *Value) GetKind() isValue_Kind {
+	if m != nil {
+		return m.Kind
+	}
+	return nil
+}
+
+func (m *Value) GetIntegerValue() int64 {
+	if x, ok := m.GetKind().(*Value_IntegerValue); ok {
+		return x.IntegerValue
+	}
+	return 0
+}
+
+func (m *Value) GetDecimalValue() float64 {
+	if x, ok := m.GetKind().(*Value_DecimalValue); ok {
+		return x.DecimalValue
+	}
+	return 0
+}
+
+func (m *Value) GetBooleanValue() bool {
+	if x, ok := m.GetKind().(*Value_BooleanValue); ok {
+		return x.BooleanValue
+	}
+	return false
+}
+
+func (m *Value) GetStringValue() string {
+	if x, ok := m.GetKind().(*Value_StringValue); ok {
+		return x.StringValue
+	}
+	return ""
+}
+
+func (m *Value) GetDecimalText() string {
+	if x, ok := m.GetKind().(*Value_DecimalText); ok {
+		return x.DecimalText
+	}
+	return ""
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*Value) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*Value_IntegerValue)(nil),
+		(*Value_DecimalValue)(nil),
+		(*Value_BooleanValue)(nil),
+		(*Value_StringValue)(nil),
+		(*Value_DecimalText)(nil),
+	}
+}
+
+type ParamConstraints struct {
+	Min                  *wrappers.DoubleValue `protobuf:"bytes,1,opt,name=min,proto3" json:"min,omitempty"`
+	Max                  *wrappers.DoubleValue `protobuf:"bytes,2,opt,name=max,proto3" json:"max,omitempty"`
+	Step                 *wrappers.DoubleValue `protobuf:"bytes,3,opt,name=step,proto3" json:"step,omitempty"`
+	Precision            *wrappers.Int32Value  `protobuf:"bytes,4,opt,name=precision,proto3" json:"precision,omitempty"`
+	MaxLength            *wrappers.Int32Value  `protobuf:"bytes,5,opt,name=max_length,json=maxLength,proto3" json:"max_length,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *ParamConstraints) Reset()         { *m = ParamConstraints{} }
+func (m *ParamConstraints) String() string { return proto.CompactTextString(m) }
+func (*ParamConstraints) ProtoMessage()    {}
+func (*ParamConstraints) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{6}
+}
+
+func (m *ParamConstraints) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ParamConstraints.Unmarshal(m, b)
+}
+func (m *ParamConstraints) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ParamConstraints.Marshal(b, m, deterministic)
+}
+func (m *ParamConstraints) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ParamConstraints.Merge(m, src)
+}
+func (m *ParamConstraints) XXX_Size() int {
+	return xxx_messageInfo_ParamConstraints.Size(m)
+}
+func (m *ParamConstraints) XXX_DiscardUnknown() {
+	xxx_messageInfo_ParamConstraints.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ParamConstraints proto.InternalMessageInfo
+
+func (m *ParamConstraints) GetMin() *wrappers.DoubleValue {
+	if m != nil {
+		return m.Min
+	}
+	return nil
+}
+
+func (m *ParamConstraints) GetMax() *wrappers.DoubleValue {
+	if m != nil {
+		return m.Max
+	}
+	return nil
+}
+
+func (m *ParamConstraints) GetStep() *wrappers.DoubleValue {
+	if m != nil {
+		return m.Step
+	}
+	return nil
+}
+
+func (m *ParamConstraints) GetPrecision() *wrappers.Int32Value {
+	if m != nil {
+		return m.Precision
+	}
+	return nil
+}
+
+func (m *ParamConstraints) GetMaxLength() *wrappers.Int32Value {
+	if m != nil {
+		return m.MaxLength
+	}
+	return nil
+}
+
+type Param struct {
+	Id                   int32             `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string            `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	ValType              string            `protobuf:"bytes,3,opt,name=val_type,json=valType,proto3" json:"val_type,omitempty"`
+	BaseType             string            `protobuf:"bytes,4,opt,name=base_type,json=baseType,proto3" json:"base_type,omitempty"`
+	Ei                   *EI               `protobuf:"bytes,5,opt,name=ei,proto3" json:"ei,omitempty"`
+	Constraints          *ParamConstraints `protobuf:"bytes,6,opt,name=constraints,proto3" json:"constraints,omitempty"`
+	Required             bool              `protobuf:"varint,7,opt,name=required,proto3" json:"required,omitempty"`
+	DefaultValue         *Value            `protobuf:"bytes,8,opt,name=default_value,json=defaultValue,proto3" json:"default_value,omitempty"`
+	DisplayEi            *EI               `protobuf:"bytes,9,opt,name=display_ei,json=displayEi,proto3" json:"display_ei,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *Param) Reset()         { *m = Param{} }
+func (m *Param) String() string { return proto.CompactTextString(m) }
+func (*Param) ProtoMessage()    {}
+func (*Param) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{7}
+}
+
+func (m *Param) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Param.Unmarshal(m, b)
+}
+func (m *Param) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Param.Marshal(b, m, deterministic)
+}
+func (m *Param) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Param.Merge(m, src)
+}
+func (m *Param) XXX_Size() int {
+	return xxx_messageInfo_Param.Size(m)
+}
+func (m *Param) XXX_DiscardUnknown() {
+	xxx_messageInfo_Param.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Param proto.InternalMessageInfo
+
+func (m *Param) GetId() int32 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Param) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Param) GetValType() string {
+	if m != nil {
+		return m.ValType
+	}
+	return ""
+}
+
+func (m *Param) GetBaseType() string {
+	if m != nil {
+		return m.BaseType
+	}
+	return ""
+}
+
+func (m *Param) GetEi() *EI {
+	if m != nil {
+		return m.Ei
+	}
+	return nil
+}
+
+func (m *Param) GetConstraints() *ParamConstraints {
+	if m != nil {
+		return m.Constraints
+	}
+	return nil
+}
+
+func (m *Param) GetRequired() bool {
+	if m != nil {
+		return m.Required
+	}
+	return false
+}
+
+func (m *Param) GetDefaultValue() *Value {
+	if m != nil {
+		return m.DefaultValue
+	}
+	return nil
+}
+
+func (m *Param) GetDisplayEi() *EI {
+	if m != nil {
+		return m.DisplayEi
+	}
+	return nil
+}
+
+type ParamOverride struct {
+	Name                 string              `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Constraints          *ParamConstraints   `protobuf:"bytes,2,opt,name=constraints,proto3" json:"constraints,omitempty"`
+	Required             *wrappers.BoolValue `protobuf:"bytes,3,opt,name=required,proto3" json:"required,omitempty"`
+	DefaultValue         *Value              `protobuf:"bytes,4,opt,name=default_value,json=defaultValue,proto3" json:"default_value,omitempty"`
+	DisplayEi            *EI                 `protobuf:"bytes,5,opt,name=display_ei,json=displayEi,proto3" json:"display_ei,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *ParamOverride) Reset()         { *m = ParamOverride{} }
+func (m *ParamOverride) String() string { return proto.CompactTextString(m) }
+func (*ParamOverride) ProtoMessage()    {}
+func (*ParamOverride) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{8}
+}
+
+func (m *ParamOverride) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ParamOverride.Unmarshal(m, b)
+}
+func (m *ParamOverride) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ParamOverride.Marshal(b, m, deterministic)
+}
+func (m *ParamOverride) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ParamOverride.Merge(m, src)
+}
+func (m *ParamOverride) XXX_Size() int {
+	return xxx_messageInfo_ParamOverride.Size(m)
+}
+func (m *ParamOverride) XXX_DiscardUnknown() {
+	xxx_messageInfo_ParamOverride.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ParamOverride proto.InternalMessageInfo
+
+func (m *ParamOverride) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ParamOverride) GetConstraints() *ParamConstraints {
+	if m != nil {
+		return m.Constraints
+	}
+	return nil
+}
+
+func (m *ParamOverride) GetRequired() *wrappers.BoolValue {
+	if m != nil {
+		return m.Required
+	}
+	return nil
+}
+
+func (m *ParamOverride) GetDefaultValue() *Value {
+	if m != nil {
+		return m.DefaultValue
+	}
+	return nil
+}
+
+func (m *ParamOverride) GetDisplayEi() *EI {
+	if m != nil {
+		return m.DisplayEi
+	}
+	return nil
+}
+
+type ParamValue struct {
+	Param *Param `protobuf:"bytes,1,opt,name=param,proto3" json:"param,omitempty"`
+	Value *Value `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	// unit the value is given in, if it differs from the EI of the param
+	Unit                 string   `protobuf:"bytes,3,opt,name=unit,proto3" json:"unit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ParamValue) Reset()         { *m = ParamValue{} }
+func (m *ParamValue) String() string { return proto.CompactTextString(m) }
+func (*ParamValue) ProtoMessage()    {}
+func (*ParamValue) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{9}
+}
+
+func (m *ParamValue) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ParamValue.Unmarshal(m, b)
+}
+func (m *ParamValue) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ParamValue.Marshal(b, m, deterministic)
+}
+func (m *ParamValue) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ParamValue.Merge(m, src)
+}
+func (m *ParamValue) XXX_Size() int {
+	return xxx_messageInfo_ParamValue.Size(m)
+}
+func (m *ParamValue) XXX_DiscardUnknown() {
+	xxx_messageInfo_ParamValue.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ParamValue proto.InternalMessageInfo
+
+func (m *ParamValue) GetParam() *Param {
+	if m != nil {
+		return m.Param
+	}
+	return nil
+}
+
+func (m *ParamValue) GetValue() *Value {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *ParamValue) GetUnit() string {
+	if m != nil {
+		return m.Unit
+	}
+	return ""
+}
+
+type Class struct {
+	Id       int32    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name     string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Children []*Class `protobuf:"bytes,3,rep,name=children,proto3" json:"children,omitempty"`
+	Ei       *EI      `protobuf:"bytes,4,opt,name=ei,proto3" json:"ei,omitempty"`
+	Params   []*Param `protobuf:"bytes,5,rep,name=params,proto3" json:"params,omitempty"`
+	// overrides are only used when the class is created
+	Overrides            []*ParamOverride `protobuf:"bytes,6,rep,name=overrides,proto3" json:"overrides,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *Class) Reset()         { *m = Class{} }
+func (m *Class) String() string { return proto.CompactTextString(m) }
+func (*Class) ProtoMessage()    {}
+func (*Class) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{10}
+}
+
+func (m *Class) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Class.Unmarshal(m, b)
+}
+func (m *Class) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Class.Marshal(b, m, deterministic)
+}
+func (m *Class) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Class.Merge(m, src)
+}
+func (m *Class) XXX_Size() int {
+	return xxx_messageInfo_Class.Size(m)
+}
+func (m *Class) XXX_DiscardUnknown() {
+	xxx_messageInfo_Class.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Class proto.InternalMessageInfo
+
+func (m *Class) GetId() int32 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Class) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Class) GetChildren() []*Class {
+	if m != nil {
+		return m.Children
+	}
+	return nil
+}
+
+func (m *Class) GetEi() *EI {
+	if m != nil {
+		return m.Ei
+	}
+	return nil
+}
+
+func (m *Class) GetParams() []*Param {
+	if m != nil {
+		return m.Params
+	}
+	return nil
+}
+
+func (m *Class) GetOverrides() []*ParamOverride {
+	if m != nil {
+		return m.Overrides
+	}
+	return nil
+}
+
+type Product struct {
+	Id                   int32         `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string        `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	ParentClass          *Class        `protobuf:"bytes,3,opt,name=parent_class,json=parentClass,proto3" json:"parent_class,omitempty"`
+	Params               []*ParamValue `protobuf:"bytes,4,rep,name=params,proto3" json:"params,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *Product) Reset()         { *m = Product{} }
+func (m *Product) String() string { return proto.CompactTextString(m) }
+func (*Product) ProtoMessage()    {}
+func (*Product) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{11}
+}
+
+func (m *Product) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Product.Unmarshal(m, b)
+}
+func (m *Product) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Product.Marshal(b, m, deterministic)
+}
+func (m *Product) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Product.Merge(m, src)
+}
+func (m *Product) XXX_Size() int {
+	return xxx_messageInfo_Product.Size(m)
+}
+func (m *Product) XXX_DiscardUnknown() {
+	xxx_messageInfo_Product.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Product proto.InternalMessageInfo
+
+func (m *Product) GetId() int32 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Product) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Product) GetParentClass() *Class {
+	if m != nil {
+		return m.ParentClass
+	}
+	return nil
+}
+
+func (m *Product) GetParams() []*ParamValue {
+	if m != nil {
+		return m.Params
+	}
+	return nil
+}
+
+type IdsResponse struct {
+	Ids                  []int32  `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IdsResponse) Reset()         { *m = IdsResponse{} }
+func (m *IdsResponse) String() string { return proto.CompactTextString(m) }
+func (*IdsResponse) ProtoMessage()    {}
+func (*IdsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{12}
+}
+
+func (m *IdsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IdsResponse.Unmarshal(m, b)
+}
+func (m *IdsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IdsResponse.Marshal(b, m, deterministic)
+}
+func (m *IdsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IdsResponse.Merge(m, src)
+}
+func (m *IdsResponse) XXX_Size() int {
+	return xxx_messageInfo_IdsResponse.Size(m)
+}
+func (m *IdsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_IdsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IdsResponse proto.InternalMessageInfo
+
+func (m *IdsResponse) GetIds() []int32 {
+	if m != nil {
+		return m.Ids
+	}
+	return nil
+}
+
+type CreateEIsRequest struct {
+	Eis                  []*EI    `protobuf:"bytes,1,rep,name=eis,proto3" json:"eis,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateEIsRequest) Reset()         { *m = CreateEIsRequest{} }
+func (m *CreateEIsRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateEIsRequest) ProtoMessage()    {}
+func (*CreateEIsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{13}
+}
+
+func (m *CreateEIsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateEIsRequest.Unmarshal(m, b)
+}
+func (m *CreateEIsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateEIsRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateEIsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateEIsRequest.Merge(m, src)
+}
+func (m *CreateEIsRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateEIsRequest.Size(m)
+}
+func (m *CreateEIsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateEIsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateEIsRequest proto.InternalMessageInfo
+
+func (m *CreateEIsRequest) GetEis() []*EI {
+	if m != nil {
+		return m.Eis
+	}
+	return nil
+}
+
+type ListEIsRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Page                 *Page    `protobuf:"bytes,2,opt,name=page,proto3" json:"page,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListEIsRequest) Reset()         { *m = ListEIsRequest{} }
+func (m *ListEIsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListEIsRequest) ProtoMessage()    {}
+func (*ListEIsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{14}
+}
+
+func (m *ListEIsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListEIsRequest.Unmarshal(m, b)
+}
+func (m *ListEIsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListEIsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListEIsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListEIsRequest.Merge(m, src)
+}
+func (m *ListEIsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListEIsRequest.Size(m)
+}
+func (m *ListEIsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListEIsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListEIsRequest proto.InternalMessageInfo
+
+func (m *ListEIsRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ListEIsRequest) GetPage() *Page {
+	if m != nil {
+		return m.Page
+	}
+	return nil
+}
+
+type ListEIsResponse struct {
+	Eis                  []*EI    `protobuf:"bytes,1,rep,name=eis,proto3" json:"eis,omitempty"`
+	Total                int32    `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListEIsResponse) Reset()         { *m = ListEIsResponse{} }
+func (m *ListEIsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListEIsResponse) ProtoMessage()    {}
+func (*ListEIsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{15}
+}
+
+func (m *ListEIsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListEIsResponse.Unmarshal(m, b)
+}
+func (m *ListEIsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListEIsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListEIsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListEIsResponse.Merge(m, src)
+}
+func (m *ListEIsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListEIsResponse.Size(m)
+}
+func (m *ListEIsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListEIsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListEIsResponse proto.InternalMessageInfo
+
+func (m *ListEIsResponse) GetEis() []*EI {
+	if m != nil {
+		return m.Eis
+	}
+	return nil
+}
+
+func (m *ListEIsResponse) GetTotal() int32 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+type CreateValueTypesRequest struct {
+	ValueTypes           []*ValueType `protobuf:"bytes,1,rep,name=value_types,json=valueTypes,proto3" json:"value_types,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *CreateValueTypesRequest) Reset()         { *m = CreateValueTypesRequest{} }
+func (m *CreateValueTypesRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateValueTypesRequest) ProtoMessage()    {}
+func (*CreateValueTypesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{16}
+}
+
+func (m *CreateValueTypesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateValueTypesRequest.Unmarshal(m, b)
+}
+func (m *CreateValueTypesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateValueTypesRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateValueTypesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateValueTypesRequest.Merge(m, src)
+}
+func (m *CreateValueTypesRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateValueTypesRequest.Size(m)
+}
+func (m *CreateValueTypesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateValueTypesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateValueTypesRequest proto.InternalMessageInfo
+
+func (m *CreateValueTypesRequest) GetValueTypes() []*ValueType {
+	if m != nil {
+		return m.ValueTypes
+	}
+	return nil
+}
+
+type ListValueTypesRequest struct {
+	Page                 *Page    `protobuf:"bytes,1,opt,name=page,proto3" json:"page,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListValueTypesRequest) Reset()         { *m = ListValueTypesRequest{} }
+func (m *ListValueTypesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListValueTypesRequest) ProtoMessage()    {}
+func (*ListValueTypesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{17}
+}
+
+func (m *ListValueTypesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListValueTypesRequest.Unmarshal(m, b)
+}
+func (m *ListValueTypesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListValueTypesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListValueTypesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListValueTypesRequest.Merge(m, src)
+}
+func (m *ListValueTypesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListValueTypesRequest.Size(m)
+}
+func (m *ListValueTypesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListValueTypesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListValueTypesRequest proto.InternalMessageInfo
+
+func (m *ListValueTypesRequest) GetPage() *Page {
+	if m != nil {
+		return m.Page
+	}
+	return nil
+}
+
+type ListValueTypesResponse struct {
+	ValueTypes           []*ValueType `protobuf:"bytes,1,rep,name=value_types,json=valueTypes,proto3" json:"value_types,omitempty"`
+	Total                int32        `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ListValueTypesResponse) Reset()         { *m = ListValueTypesResponse{} }
+func (m *ListValueTypesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListValueTypesResponse) ProtoMessage()    {}
+func (*ListValueTypesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{18}
+}
+
+func (m *ListValueTypesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListValueTypesResponse.Unmarshal(m, b)
+}
+func (m *ListValueTypesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListValueTypesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListValueTypesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListValueTypesResponse.Merge(m, src)
+}
+func (m *ListValueTypesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListValueTypesResponse.Size(m)
+}
+func (m *ListValueTypesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListValueTypesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListValueTypesResponse proto.InternalMessageInfo
+
+func (m *ListValueTypesResponse) GetValueTypes() []*ValueType {
+	if m != nil {
+		return m.ValueTypes
+	}
+	return nil
+}
+
+func (m *ListValueTypesResponse) GetTotal() int32 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+type CreateClassesRequest struct {
+	Classes              []*Class `protobuf:"bytes,1,rep,name=classes,proto3" json:"classes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateClassesRequest) Reset()         { *m = CreateClassesRequest{} }
+func (m *CreateClassesRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateClassesRequest) ProtoMessage()    {}
+func (*CreateClassesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{19}
+}
+
+func (m *CreateClassesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateClassesRequest.Unmarshal(m, b)
+}
+func (m *CreateClassesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateClassesRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateClassesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateClassesRequest.Merge(m, src)
+}
+func (m *CreateClassesRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateClassesRequest.Size(m)
+}
+func (m *CreateClassesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateClassesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateClassesRequest proto.InternalMessageInfo
+
+func (m *CreateClassesRequest) GetClasses() []*Class {
+	if m != nil {
+		return m.Classes
+	}
+	return nil
+}
+
+type GetClassRequest struct {
+	Id int32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// include the params inherited from the ancestors
+	AllParams            bool     `protobuf:"varint,2,opt,name=all_params,json=allParams,proto3" json:"all_params,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetClassRequest) Reset()         { *m = GetClassRequest{} }
+func (m *GetClassRequest) String() string { return proto.CompactTextString(m) }
+func (*GetClassRequest) ProtoMessage()    {}
+func (*GetClassRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{20}
+}
+
+func (m *GetClassRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetClassRequest.Unmarshal(m, b)
+}
+func (m *GetClassRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetClassRequest.Marshal(b, m, deterministic)
+}
+func (m *GetClassRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetClassRequest.Merge(m, src)
+}
+func (m *GetClassRequest) XXX_Size() int {
+	return xxx_messageInfo_GetClassRequest.Size(m)
+}
+func (m *GetClassRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetClassRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetClassRequest proto.InternalMessageInfo
+
+func (m *GetClassRequest) GetId() int32 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *GetClassRequest) GetAllParams() bool {
+	if m != nil {
+		return m.AllParams
+	}
+	return false
+}
+
+type ClassTree struct {
+	Classes              []*Class `protobuf:"bytes,1,rep,name=classes,proto3" json:"classes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClassTree) Reset()         { *m = ClassTree{} }
+func (m *ClassTree) String() string { return proto.CompactTextString(m) }
+func (*ClassTree) ProtoMessage()    {}
+func (*ClassTree) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{21}
+}
+
+func (m *ClassTree) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClassTree.Unmarshal(m, b)
+}
+func (m *ClassTree) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClassTree.Marshal(b, m, deterministic)
+}
+func (m *ClassTree) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClassTree.Merge(m, src)
+}
+func (m *ClassTree) XXX_Size() int {
+	return xxx_messageInfo_ClassTree.Size(m)
+}
+func (m *ClassTree) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClassTree.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClassTree proto.InternalMessageInfo
+
+func (m *ClassTree) GetClasses() []*Class {
+	if m != nil {
+		return m.Classes
+	}
+	return nil
+}
+
+type DeleteClassRequest struct {
+	Id                   int32    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteClassRequest) Reset()         { *m = DeleteClassRequest{} }
+func (m *DeleteClassRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteClassRequest) ProtoMessage()    {}
+func (*DeleteClassRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{22}
+}
+
+func (m *DeleteClassRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteClassRequest.Unmarshal(m, b)
+}
+func (m *DeleteClassRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteClassRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteClassRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteClassRequest.Merge(m, src)
+}
+func (m *DeleteClassRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteClassRequest.Size(m)
+}
+func (m *DeleteClassRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteClassRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteClassRequest proto.InternalMessageInfo
+
+func (m *DeleteClassRequest) GetId() int32 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+type CreateProductsRequest struct {
+	Products             []*Product `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *CreateProductsRequest) Reset()         { *m = CreateProductsRequest{} }
+func (m *CreateProductsRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateProductsRequest) ProtoMessage()    {}
+func (*CreateProductsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{23}
+}
+
+func (m *CreateProductsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateProductsRequest.Unmarshal(m, b)
+}
+func (m *CreateProductsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateProductsRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateProductsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateProductsRequest.Merge(m, src)
+}
+func (m *CreateProductsRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateProductsRequest.Size(m)
+}
+func (m *CreateProductsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateProductsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateProductsRequest proto.InternalMessageInfo
+
+func (m *CreateProductsRequest) GetProducts() []*Product {
+	if m != nil {
+		return m.Products
+	}
+	return nil
+}
+
+type GetProductRequest struct {
+	Id                   int32    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Unit                 string   `protobuf:"bytes,2,opt,name=unit,proto3" json:"unit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetProductRequest) Reset()         { *m = GetProductRequest{} }
+func (m *GetProductRequest) String() string { return proto.CompactTextString(m) }
+func (*GetProductRequest) ProtoMessage()    {}
+func (*GetProductRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{24}
+}
+
+func (m *GetProductRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetProductRequest.Unmarshal(m, b)
+}
+func (m *GetProductRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetProductRequest.Marshal(b, m, deterministic)
+}
+func (m *GetProductRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetProductRequest.Merge(m, src)
+}
+func (m *GetProductRequest) XXX_Size() int {
+	return xxx_messageInfo_GetProductRequest.Size(m)
+}
+func (m *GetProductRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetProductRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetProductRequest proto.InternalMessageInfo
+
+func (m *GetProductRequest) GetId() int32 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *GetProductRequest) GetUnit() string {
+	if m != nil {
+		return m.Unit
+	}
+	return ""
+}
+
+type UpdateProductRequest struct {
+	Product              *Product `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateProductRequest) Reset()         { *m = UpdateProductRequest{} }
+func (m *UpdateProductRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateProductRequest) ProtoMessage()    {}
+func (*UpdateProductRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{25}
+}
+
+func (m *UpdateProductRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateProductRequest.Unmarshal(m, b)
+}
+func (m *UpdateProductRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateProductRequest.Marshal(b, m, deterministic)
+}
+func (m *UpdateProductRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateProductRequest.Merge(m, src)
+}
+func (m *UpdateProductRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateProductRequest.Size(m)
+}
+func (m *UpdateProductRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateProductRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateProductRequest proto.InternalMessageInfo
+
+func (m *UpdateProductRequest) GetProduct() *Product {
+	if m != nil {
+		return m.Product
+	}
+	return nil
+}
+
+type DeleteProductRequest struct {
+	Id                   int32    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteProductRequest) Reset()         { *m = DeleteProductRequest{} }
+func (m *DeleteProductRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteProductRequest) ProtoMessage()    {}
+func (*DeleteProductRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{26}
+}
+
+func (m *DeleteProductRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteProductRequest.Unmarshal(m, b)
+}
+func (m *DeleteProductRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteProductRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteProductRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteProductRequest.Merge(m, src)
+}
+func (m *DeleteProductRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteProductRequest.Size(m)
+}
+func (m *DeleteProductRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteProductRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteProductRequest proto.InternalMessageInfo
+
+func (m *DeleteProductRequest) GetId() int32 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+type ListProductsRequest struct {
+	ClassId              int32    `protobuf:"varint,1,opt,name=class_id,json=classId,proto3" json:"class_id,omitempty"`
+	Unit                 string   `protobuf:"bytes,2,opt,name=unit,proto3" json:"unit,omitempty"`
+	Sort                 string   `protobuf:"bytes,3,opt,name=sort,proto3" json:"sort,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListProductsRequest) Reset()         { *m = ListProductsRequest{} }
+func (m *ListProductsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListProductsRequest) ProtoMessage()    {}
+func (*ListProductsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{27}
+}
+
+func (m *ListProductsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListProductsRequest.Unmarshal(m, b)
+}
+func (m *ListProductsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListProductsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListProductsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListProductsRequest.Merge(m, src)
+}
+func (m *ListProductsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListProductsRequest.Size(m)
+}
+func (m *ListProductsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListProductsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListProductsRequest proto.InternalMessageInfo
+
+func (m *ListProductsRequest) GetClassId() int32 {
+	if m != nil {
+		return m.ClassId
+	}
+	return 0
+}
+
+func (m *ListProductsRequest) GetUnit() string {
+	if m != nil {
+		return m.Unit
+	}
+	return ""
+}
+
+func (m *ListProductsRequest) GetSort() string {
+	if m != nil {
+		return m.Sort
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*Empty)(nil), "hsesql.catalog.Empty")
+	proto.RegisterType((*Page)(nil), "hsesql.catalog.Page")
+	proto.RegisterType((*EI)(nil), "hsesql.catalog.EI")
+	proto.RegisterType((*EnumValue)(nil), "hsesql.catalog.EnumValue")
+	proto.RegisterType((*ValueType)(nil), "hsesql.catalog.ValueType")
+	proto.RegisterType((*Value)(nil), "hsesql.catalog.Value")
+	proto.RegisterType((*ParamConstraints)(nil), "hsesql.catalog.ParamConstraints")
+	proto.RegisterType((*Param)(nil), "hsesql.catalog.Param")
+	proto.RegisterType((*ParamOverride)(nil), "hsesql.catalog.ParamOverride")
+	proto.RegisterType((*ParamValue)(nil), "hsesql.catalog.ParamValue")
+	proto.RegisterType((*Class)(nil), "hsesql.catalog.Class")
+	proto.RegisterType((*Product)(nil), "hsesql.catalog.Product")
+	proto.RegisterType((*IdsResponse)(nil), "hsesql.catalog.IdsResponse")
+	proto.RegisterType((*CreateEIsRequest)(nil), "hsesql.catalog.CreateEIsRequest")
+	proto.RegisterType((*ListEIsRequest)(nil), "hsesql.catalog.ListEIsRequest")
+	proto.RegisterType((*ListEIsResponse)(nil), "hsesql.catalog.ListEIsResponse")
+	proto.RegisterType((*CreateValueTypesRequest)(nil), "hsesql.catalog.CreateValueTypesRequest")
+	proto.RegisterType((*ListValueTypesRequest)(nil), "hsesql.catalog.ListValueTypesRequest")
+	proto.RegisterType((*ListValueTypesResponse)(nil), "hsesql.catalog.ListValueTypesResponse")
+	proto.RegisterType((*CreateClassesRequest)(nil), "hsesql.catalog.CreateClassesRequest")
+	proto.RegisterType((*GetClassRequest)(nil), "hsesql.catalog.GetClassRequest")
+	proto.RegisterType((*ClassTree)(nil), "hsesql.catalog.ClassTree")
+	proto.RegisterType((*DeleteClassRequest)(nil), "hsesql.catalog.DeleteClassRequest")
+	proto.RegisterType((*CreateProductsRequest)(nil), "hsesql.catalog.CreateProductsRequest")
+	proto.RegisterType((*GetProductRequest)(nil), "hsesql.catalog.GetProductRequest")
+	proto.RegisterType((*UpdateProductRequest)(nil), "hsesql.catalog.UpdateProductRequest")
+	proto.RegisterType((*DeleteProductRequest)(nil), "hsesql.catalog.DeleteProductRequest")
+	proto.RegisterType((*ListProductsRequest)(nil), "hsesql.catalog.ListProductsRequest")
+}
+
+func init() {
+	proto.RegisterFile("catalog.proto", fileDescriptor_0abbfcf058acdf89)
+}
+
+var fileDescriptor_0abbfcf058acdf89 = []byte{
+	// 1358 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x57, 0x5d, 0x73, 0xdb, 0x44,
+	0x17, 0xae, 0x64, 0x2b, 0xb6, 0x8e, 0x93, 0xb4, 0xef, 0xbe, 0x69, 0xab, 0xb8, 0x5f, 0x46, 0xfd,
+	0x20, 0x33, 0x0c, 0x4e, 0xe3, 0xce, 0x40, 0x69, 0xb9, 0x28, 0x49, 0x33, 0x8d, 0x4b, 0x28, 0x41,
+	0xb4, 0x0c, 0xc3, 0x8d, 0x67, 0x63, 0x6d, 0x9c, 0x05, 0x59, 0x52, 0xb4, 0xeb, 0x90, 0x70, 0xc3,
+	0x3f, 0xe0, 0x8e, 0xab, 0xfe, 0x27, 0x2e, 0xf9, 0x19, 0xfc, 0x00, 0xae, 0x98, 0xfd, 0x90, 0x6c,
+	0xeb, 0x23, 0x35, 0xe5, 0x4e, 0x7b, 0xf6, 0xd9, 0xb3, 0xcf, 0x73, 0xf6, 0x39, 0xbb, 0x36, 0xac,
+	0x0c, 0x31, 0xc7, 0x41, 0x34, 0xea, 0xc6, 0x49, 0xc4, 0x23, 0xb4, 0x7a, 0xcc, 0x08, 0x3b, 0x09,
+	0xba, 0x3a, 0xda, 0xbe, 0x3d, 0x8a, 0xa2, 0x51, 0x40, 0x36, 0xe5, 0xec, 0xe1, 0xe4, 0x68, 0xf3,
+	0xe7, 0x04, 0xc7, 0x31, 0x49, 0x98, 0xc2, 0xbb, 0x0d, 0xb0, 0x76, 0xc7, 0x31, 0x3f, 0x77, 0xf7,
+	0xa0, 0x7e, 0x80, 0x47, 0x04, 0xad, 0x81, 0x15, 0xd0, 0x31, 0xe5, 0x8e, 0xd1, 0x31, 0x36, 0x2c,
+	0x4f, 0x0d, 0xd0, 0x35, 0x58, 0x8a, 0x8e, 0x8e, 0x18, 0xe1, 0x8e, 0x29, 0xc3, 0x7a, 0x84, 0x10,
+	0xd4, 0x59, 0x94, 0x70, 0xa7, 0xd6, 0x31, 0x36, 0x6c, 0x4f, 0x7e, 0xbb, 0xbf, 0x1b, 0x60, 0xee,
+	0xf6, 0xd1, 0x2a, 0x98, 0xd4, 0xd7, 0x59, 0x4c, 0xea, 0x0b, 0x68, 0x88, 0xc7, 0x44, 0x26, 0xb0,
+	0x3d, 0xf9, 0x8d, 0x6e, 0x01, 0xb0, 0xe3, 0x28, 0xe1, 0x03, 0x39, 0xa3, 0x92, 0xd8, 0x32, 0xf2,
+	0x4a, 0x4c, 0xdf, 0x04, 0xdb, 0xa7, 0x63, 0x12, 0x32, 0x1a, 0x85, 0x4e, 0x5d, 0xcd, 0x66, 0x01,
+	0xc1, 0xe9, 0x08, 0x0f, 0x79, 0x94, 0x38, 0x56, 0xc7, 0xd8, 0x30, 0x3c, 0x3d, 0x9a, 0xe1, 0xba,
+	0xa4, 0xe2, 0x6a, 0xe4, 0x7e, 0x09, 0xf6, 0x6e, 0x38, 0x19, 0x7f, 0x87, 0x83, 0x09, 0x29, 0xb0,
+	0x5b, 0x03, 0xeb, 0x54, 0x4c, 0x68, 0x7a, 0x6a, 0x80, 0x1c, 0x68, 0x24, 0x84, 0xd3, 0x84, 0xf8,
+	0x92, 0x5c, 0xd3, 0x4b, 0x87, 0xee, 0xaf, 0x60, 0xcb, 0x44, 0xaf, 0xcf, 0x63, 0xb2, 0x90, 0xd4,
+	0x1b, 0x60, 0x1f, 0x62, 0x46, 0x06, 0xfc, 0x3c, 0x4e, 0x95, 0x36, 0x45, 0x40, 0x26, 0xd8, 0x82,
+	0x25, 0xb9, 0x21, 0x73, 0xea, 0x9d, 0xda, 0x46, 0xab, 0xb7, 0xde, 0x9d, 0x3f, 0xc6, 0x6e, 0x46,
+	0xdc, 0xd3, 0x40, 0xf7, 0x0f, 0x03, 0x2c, 0x25, 0xe5, 0x3e, 0xac, 0xd0, 0x90, 0x93, 0x11, 0x49,
+	0x06, 0x4a, 0x82, 0x20, 0x52, 0xdb, 0xbb, 0xe4, 0x2d, 0xeb, 0x70, 0x06, 0xf3, 0xc9, 0x90, 0x8e,
+	0x71, 0x30, 0x98, 0x2a, 0x35, 0x04, 0x4c, 0x87, 0x33, 0xd8, 0x61, 0x14, 0x05, 0x04, 0x87, 0x1a,
+	0x26, 0x85, 0x0b, 0x98, 0x0e, 0x2b, 0xd8, 0x5d, 0x58, 0x66, 0x3c, 0xa1, 0xe1, 0x48, 0xa3, 0xe4,
+	0xe9, 0xec, 0x5d, 0xf2, 0x5a, 0x2a, 0x9a, 0x81, 0xd2, 0x2d, 0x39, 0x39, 0xe3, 0x8e, 0x95, 0x82,
+	0x74, 0xf4, 0x35, 0x39, 0xe3, 0xdb, 0x4b, 0x50, 0xff, 0x89, 0x86, 0xbe, 0xfb, 0xd6, 0x84, 0x2b,
+	0x07, 0x38, 0xc1, 0xe3, 0x9d, 0x28, 0x64, 0x3c, 0xc1, 0x34, 0xe4, 0x0c, 0x75, 0xa1, 0x36, 0xa6,
+	0xa1, 0x54, 0xd4, 0xea, 0xdd, 0xec, 0x2a, 0x33, 0x77, 0x53, 0x33, 0x77, 0x9f, 0x47, 0x93, 0xc3,
+	0x80, 0xa8, 0xc2, 0x08, 0xa0, 0xc4, 0xe3, 0x33, 0xc7, 0x5c, 0x08, 0x8f, 0xcf, 0xd0, 0x43, 0xa8,
+	0x33, 0x4e, 0x62, 0xa7, 0xb6, 0xc0, 0x02, 0x89, 0x44, 0x9f, 0x81, 0x1d, 0x27, 0x64, 0x48, 0x33,
+	0x4f, 0xb6, 0x7a, 0x37, 0x0a, 0xcb, 0xfa, 0x21, 0x7f, 0xd4, 0x53, 0xab, 0xa6, 0x68, 0xf4, 0x04,
+	0x60, 0x8c, 0xcf, 0x06, 0x01, 0x09, 0x47, 0xfc, 0xd8, 0xb1, 0x16, 0x58, 0x3b, 0xc6, 0x67, 0xfb,
+	0x12, 0xed, 0xfe, 0x69, 0x82, 0x25, 0xab, 0xb3, 0x90, 0xd9, 0xd6, 0xa1, 0x79, 0x8a, 0x83, 0x59,
+	0xaf, 0x35, 0x4e, 0x71, 0x20, 0xad, 0x36, 0xe7, 0xc3, 0x7a, 0xce, 0x87, 0x2e, 0x98, 0x84, 0x6a,
+	0x66, 0xa8, 0xe0, 0xc1, 0xbe, 0x67, 0x12, 0x8a, 0xb6, 0xa1, 0x35, 0x9c, 0x9e, 0x90, 0xec, 0xb1,
+	0x56, 0xaf, 0x93, 0x07, 0xe7, 0x4f, 0xd2, 0x9b, 0x5d, 0x84, 0xda, 0xd0, 0x4c, 0xc8, 0xc9, 0x44,
+	0x36, 0x56, 0x43, 0x36, 0x56, 0x36, 0x46, 0x4f, 0x84, 0x4f, 0x8f, 0xf0, 0x24, 0xe0, 0xda, 0x5a,
+	0x4d, 0xb9, 0xc3, 0xd5, 0xfc, 0x0e, 0xaa, 0x44, 0xcb, 0x1a, 0x2b, 0x47, 0x68, 0x0b, 0xc0, 0xa7,
+	0x2c, 0x0e, 0xf0, 0xf9, 0x80, 0x50, 0xc7, 0xae, 0xd4, 0x61, 0x6b, 0xd4, 0x2e, 0x75, 0x7f, 0x33,
+	0x61, 0x45, 0x92, 0xfd, 0xfa, 0x94, 0x24, 0x09, 0xf5, 0x49, 0x56, 0x50, 0x63, 0xa6, 0xa0, 0x39,
+	0xd1, 0xe6, 0xfb, 0x88, 0xfe, 0x64, 0x46, 0xb4, 0xf2, 0x5b, 0xbb, 0x70, 0xf8, 0xdb, 0x51, 0xa4,
+	0xfa, 0xf0, 0xa2, 0x82, 0xd4, 0xdf, 0xb7, 0x20, 0xd6, 0x22, 0x05, 0xf9, 0x05, 0x40, 0xea, 0x50,
+	0x09, 0x3e, 0x02, 0x2b, 0x16, 0x23, 0xc7, 0x28, 0xdf, 0x54, 0x42, 0x3d, 0x85, 0x11, 0xe0, 0xe9,
+	0xd5, 0x52, 0xc9, 0x50, 0x61, 0x44, 0x99, 0x27, 0x21, 0xcd, 0x9e, 0x0e, 0xf1, 0xed, 0xfe, 0x65,
+	0x80, 0xb5, 0x13, 0x60, 0xc6, 0x16, 0x72, 0xf9, 0x16, 0x34, 0x87, 0xc7, 0x34, 0xf0, 0x13, 0x12,
+	0x3a, 0xb5, 0x4e, 0xad, 0x6c, 0x47, 0x99, 0xcc, 0xcb, 0x60, 0xda, 0xe0, 0xf5, 0x0b, 0x0d, 0xfe,
+	0x31, 0x2c, 0x49, 0x39, 0xcc, 0xb1, 0x3a, 0xb5, 0x6a, 0xcd, 0x1a, 0x84, 0x9e, 0x82, 0x1d, 0x69,
+	0xeb, 0x88, 0x6e, 0x10, 0x2b, 0x6e, 0x95, 0xae, 0x48, 0x0d, 0xe6, 0x4d, 0xf1, 0xee, 0x5b, 0x03,
+	0x1a, 0x07, 0x49, 0xe4, 0x4f, 0x86, 0x7c, 0x21, 0xc9, 0x8f, 0x61, 0x39, 0xc6, 0x09, 0x09, 0xf9,
+	0x60, 0x28, 0x94, 0x69, 0x1f, 0x55, 0xc8, 0x6e, 0x29, 0xa8, 0x1c, 0xa0, 0x5e, 0xa6, 0x4a, 0x3d,
+	0x31, 0xed, 0x52, 0x8e, 0xfa, 0x8d, 0x51, 0x48, 0xf7, 0x0e, 0xb4, 0xfa, 0x3e, 0xf3, 0x08, 0x8b,
+	0xa3, 0x90, 0x11, 0x74, 0x05, 0x6a, 0xd4, 0x67, 0x8e, 0xd1, 0xa9, 0x6d, 0x58, 0x9e, 0xf8, 0x74,
+	0x1f, 0xc3, 0x95, 0x9d, 0x84, 0x60, 0x4e, 0x76, 0xfb, 0xcc, 0x23, 0x27, 0x13, 0xc2, 0x38, 0xba,
+	0x07, 0x35, 0x42, 0x15, 0xaa, 0xbc, 0xc6, 0x62, 0xda, 0x7d, 0x05, 0xab, 0xfb, 0x94, 0xf1, 0x99,
+	0x75, 0x65, 0x6d, 0xb7, 0x01, 0xf5, 0x18, 0x8f, 0x52, 0x3f, 0xad, 0x15, 0x29, 0x8f, 0x88, 0x27,
+	0x11, 0xee, 0x57, 0x70, 0x39, 0xcb, 0xa7, 0xe9, 0x2e, 0x44, 0x44, 0x3c, 0xfc, 0x3c, 0xe2, 0x38,
+	0xd0, 0x3f, 0x6c, 0xd4, 0xc0, 0x7d, 0x03, 0xd7, 0x95, 0xb0, 0xec, 0x91, 0xcf, 0x78, 0x3e, 0x81,
+	0x96, 0x34, 0xb0, 0xbc, 0x41, 0xd3, 0xf4, 0xeb, 0xa5, 0x56, 0x17, 0xeb, 0x3c, 0x38, 0xcd, 0x52,
+	0xb8, 0x5f, 0xc0, 0x55, 0xc1, 0xb2, 0x98, 0x34, 0x15, 0x6a, 0xbc, 0x53, 0xe8, 0x8f, 0x70, 0x2d,
+	0x9f, 0x42, 0xeb, 0xfd, 0x0f, 0xc4, 0x2a, 0xaa, 0xf0, 0x02, 0xd6, 0x54, 0x15, 0xa4, 0x85, 0xa6,
+	0x6c, 0x37, 0xa1, 0x31, 0x54, 0x11, 0xbd, 0x4b, 0x85, 0x01, 0x53, 0x94, 0xfb, 0x0c, 0x2e, 0xbf,
+	0x20, 0xca, 0x88, 0x69, 0x8e, 0xbc, 0xdb, 0x6f, 0x01, 0xe0, 0x20, 0x18, 0x68, 0x8f, 0x9a, 0xf2,
+	0x51, 0xb0, 0x71, 0x10, 0x1c, 0x28, 0x2b, 0x7e, 0x0e, 0xb6, 0x5c, 0xfe, 0x3a, 0x21, 0xe4, 0xdf,
+	0xef, 0x7f, 0x0f, 0xd0, 0x73, 0x12, 0x10, 0x4e, 0x2e, 0xa2, 0xe0, 0xee, 0xc3, 0x55, 0x25, 0x57,
+	0x77, 0x64, 0x06, 0x7c, 0x04, 0xcd, 0x58, 0x87, 0xf4, 0x86, 0xd7, 0x0b, 0x27, 0xa4, 0xe6, 0xbd,
+	0x0c, 0xe8, 0x7e, 0x0a, 0xff, 0x7b, 0x41, 0x78, 0x1a, 0xaf, 0x50, 0x9d, 0x5e, 0x82, 0xe6, 0xcc,
+	0x25, 0xd8, 0x87, 0xb5, 0x37, 0xb1, 0x3f, 0xa5, 0x91, 0xae, 0xdd, 0x82, 0x86, 0x4e, 0xae, 0x6d,
+	0x52, 0x49, 0x22, 0xc5, 0xb9, 0x0f, 0x60, 0x4d, 0xe9, 0xbe, 0x98, 0x86, 0xfb, 0x3d, 0xfc, 0x5f,
+	0x98, 0x2a, 0xaf, 0x7b, 0x1d, 0x9a, 0xb2, 0x82, 0x83, 0x0c, 0xac, 0x2a, 0xda, 0x2f, 0x25, 0x5e,
+	0xf6, 0x67, 0xa0, 0xf7, 0x77, 0x03, 0x1a, 0x3b, 0x8a, 0x1e, 0x7a, 0x09, 0x76, 0x76, 0x5b, 0xa0,
+	0xc2, 0xe3, 0x99, 0xbf, 0x48, 0xda, 0x37, 0xf2, 0x88, 0xd9, 0xbb, 0xe8, 0x25, 0x34, 0x74, 0xbf,
+	0xa3, 0xdb, 0x79, 0xdc, 0xfc, 0xc5, 0xd2, 0xbe, 0x53, 0x39, 0xaf, 0x73, 0x79, 0xe9, 0x2d, 0x36,
+	0x6d, 0x2a, 0xf4, 0x61, 0x39, 0xbd, 0x42, 0xe7, 0xb6, 0x0b, 0xd6, 0x93, 0x7f, 0xa7, 0xd0, 0x40,
+	0xdd, 0x6f, 0x33, 0x19, 0xef, 0x97, 0xd1, 0x28, 0xe6, 0x7b, 0xf0, 0x2e, 0x98, 0x26, 0xbd, 0x0f,
+	0x2b, 0x73, 0xbd, 0x89, 0xee, 0x95, 0x33, 0x9e, 0x6f, 0xdd, 0x2a, 0xba, 0xdb, 0xd0, 0x4c, 0x1b,
+	0x14, 0x15, 0xea, 0x95, 0x6b, 0xdd, 0x76, 0x79, 0xb7, 0xa1, 0x67, 0xb0, 0x9c, 0x22, 0x65, 0x97,
+	0x96, 0x6f, 0xd5, 0x5e, 0x2f, 0x5d, 0x2d, 0x57, 0xec, 0x41, 0x6b, 0xa6, 0x4d, 0x91, 0x9b, 0x47,
+	0x16, 0x7b, 0xb8, 0x4a, 0xcf, 0x2b, 0x58, 0x9d, 0x6f, 0xe5, 0x62, 0xf9, 0x4b, 0x5b, 0xbd, 0x2a,
+	0xdf, 0x1e, 0xc0, 0xb4, 0x99, 0xd1, 0x07, 0x25, 0x15, 0x9a, 0xef, 0xb0, 0x76, 0x55, 0x6f, 0x8a,
+	0x73, 0x9b, 0xeb, 0xee, 0xe2, 0xb9, 0x95, 0x35, 0x7f, 0x15, 0xaf, 0x7d, 0x58, 0x99, 0x6b, 0xf0,
+	0x62, 0xb6, 0xb2, 0xfe, 0xaf, 0xae, 0xda, 0xf2, 0xec, 0x35, 0x80, 0xee, 0x96, 0x79, 0x31, 0x5f,
+	0xb1, 0x2a, 0xa5, 0x0f, 0x8d, 0xed, 0xfb, 0x3f, 0xdc, 0x3d, 0x66, 0xe4, 0xdb, 0x6f, 0xf6, 0x37,
+	0xc5, 0x3f, 0xd1, 0x24, 0xc4, 0xc1, 0xa6, 0x06, 0xc5, 0x87, 0x4f, 0xb3, 0xaf, 0xc3, 0x25, 0xf9,
+	0xf3, 0xf7, 0xd1, 0x3f, 0x03, 0x00, 0x4b, 0x46, 0x4e, 0xd2, 0xcb, 0x10, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// CatalogClient is the client API for Catalog service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type CatalogClient interface {
+	CreateEIs(ctx context.Context, in *CreateEIsRequest, opts ...grpc.CallOption) (*IdsResponse, error)
+	ListEIs(ctx context.Context, in *ListEIsRequest, opts ...grpc.CallOption) (*ListEIsResponse, error)
+	CreateValueTypes(ctx context.Context, in *CreateValueTypesRequest, opts ...grpc.CallOption) (*Empty, error)
+	ListValueTypes(ctx context.Context, in *ListValueTypesRequest, opts ...grpc.CallOption) (*ListValueTypesResponse, error)
+	CreateClasses(ctx context.Context, in *CreateClassesRequest, opts ...grpc.CallOption) (*Empty, error)
+	GetClass(ctx context.Context, in *GetClassRequest, opts ...grpc.CallOption) (*Class, error)
+	GetClassTree(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ClassTree, error)
+	DeleteClass(ctx context.Context, in *DeleteClassRequest, opts ...grpc.CallOption) (*Empty, error)
+	CreateProducts(ctx context.Context, in *CreateProductsRequest, opts ...grpc.CallOption) (*Empty, error)
+	GetProduct(ctx context.Context, in *GetProductRequest, opts ...grpc.CallOption) (*Product, error)
+	UpdateProduct(ctx context.Context, in *UpdateProductRequest, opts ...grpc.CallOption) (*Empty, error)
+	DeleteProduct(ctx context.Context, in *DeleteProductRequest, opts ...grpc.CallOption) (*Empty, error)
+	// ListProducts streams all the products of a class without paging
+	ListProducts(ctx context.Context, in *ListProductsRequest, opts ...grpc.CallOption) (Catalog_ListProductsClient, error)
+}
+
+type catalogClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCatalogClient(cc grpc.ClientConnInterface) CatalogClient {
+	return &catalogClient{cc}
+}
+
+func (c *catalogClient) CreateEIs(ctx context.Context, in *CreateEIsRequest, opts ...grpc.CallOption) (*IdsResponse, error) {
+	out := new(IdsResponse)
+	err := c.cc.Invoke(ctx, "/hsesql.catalog.Catalog/CreateEIs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) ListEIs(ctx context.Context, in *ListEIsRequest, opts ...grpc.CallOption) (*ListEIsResponse, error) {
+	out := new(ListEIsResponse)
+	err := c.cc.Invoke(ctx, "/hsesql.catalog.Catalog/ListEIs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) CreateValueTypes(ctx context.Context, in *CreateValueTypesRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/hsesql.catalog.Catalog/CreateValueTypes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) ListValueTypes(ctx context.Context, in *ListValueTypesRequest, opts ...grpc.CallOption) (*ListValueTypesResponse, error) {
+	out := new(ListValueTypesResponse)
+	err := c.cc.Invoke(ctx, "/hsesql.catalog.Catalog/ListValueTypes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) CreateClasses(ctx context.Context, in *CreateClassesRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/hsesql.catalog.Catalog/CreateClasses", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) GetClass(ctx context.Context, in *GetClassRequest, opts ...grpc.CallOption) (*Class, error) {
+	out := new(Class)
+	err := c.cc.Invoke(ctx, "/hsesql.catalog.Catalog/GetClass", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) GetClassTree(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ClassTree, error) {
+	out := new(ClassTree)
+	err := c.cc.Invoke(ctx, "/hsesql.catalog.Catalog/GetClassTree", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) DeleteClass(ctx context.Context, in *DeleteClassRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/hsesql.catalog.Catalog/DeleteClass", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) CreateProducts(ctx context.Context, in *CreateProductsRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/hsesql.catalog.Catalog/CreateProducts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) GetProduct(ctx context.Context, in *GetProductRequest, opts ...grpc.CallOption) (*Product, error) {
+	out := new(Product)
+	err := c.cc.Invoke(ctx, "/hsesql.catalog.Catalog/GetProduct", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) UpdateProduct(ctx context.Context, in *UpdateProductRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/hsesql.catalog.Catalog/UpdateProduct", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) DeleteProduct(ctx context.Context, in *DeleteProductRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/hsesql.catalog.Catalog/DeleteProduct", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) ListProducts(ctx context.Context, in *ListProductsRequest, opts ...grpc.CallOption) (Catalog_ListProductsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Catalog_serviceDesc.Streams[0], "/hsesql.catalog.Catalog/ListProducts", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &catalogListProductsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Catalog_ListProductsClient interface {
+	Recv() (*Product, error)
+	grpc.ClientStream
+}
+
+type catalogListProductsClient struct {
+	grpc.ClientStream
+}
+
+func (x *catalogListProductsClient) Recv() (*Product, error) {
+	m := new(Product)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// CatalogServer is the server API for Catalog service.
+type CatalogServer interface {
+	CreateEIs(context.Context, *CreateEIsRequest) (*IdsResponse, error)
+	ListEIs(context.Context, *ListEIsRequest) (*ListEIsResponse, error)
+	CreateValueTypes(context.Context, *CreateValueTypesRequest) (*Empty, error)
+	ListValueTypes(context.Context, *ListValueTypesRequest) (*ListValueTypesResponse, error)
+	CreateClasses(context.Context, *CreateClassesRequest) (*Empty, error)
+	GetClass(context.Context, *GetClassRequest) (*Class, error)
+	GetClassTree(context.Context, *Empty) (*ClassTree, error)
+	DeleteClass(context.Context, *DeleteClassRequest) (*Empty, error)
+	CreateProducts(context.Context, *CreateProductsRequest) (*Empty, error)
+	GetProduct(context.Context, *GetProductRequest) (*Product, error)
+	UpdateProduct(context.Context, *UpdateProductRequest) (*Empty, error)
+	DeleteProduct(context.Context, *DeleteProductRequest) (*Empty, error)
+	// ListProducts streams all the products of a class without paging
+	ListProducts(*ListProductsRequest, Catalog_ListProductsServer) error
+}
+
+// UnimplementedCatalogServer can be embedded to have forward compatible implementations.
+type UnimplementedCatalogServer struct {
+}
+
+func (*UnimplementedCatalogServer) CreateEIs(ctx context.Context, req *CreateEIsRequest) (*IdsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateEIs not implemented")
+}
+func (*UnimplementedCatalogServer) ListEIs(ctx context.Context, req *ListEIsRequest) (*ListEIsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEIs not implemented")
+}
+func (*UnimplementedCatalogServer) CreateValueTypes(ctx context.Context, req *CreateValueTypesRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateValueTypes not implemented")
+}
+func (*UnimplementedCatalogServer) ListValueTypes(ctx context.Context, req *ListValueTypesRequest) (*ListValueTypesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListValueTypes not implemented")
+}
+func (*UnimplementedCatalogServer) CreateClasses(ctx context.Context, req *CreateClassesRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateClasses not implemented")
+}
+func (*UnimplementedCatalogServer) GetClass(ctx context.Context, req *GetClassRequest) (*Class, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetClass not implemented")
+}
+func (*UnimplementedCatalogServer) GetClassTree(ctx context.Context, req *Empty) (*ClassTree, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetClassTree not implemented")
+}
+func (*UnimplementedCatalogServer) DeleteClass(ctx context.Context, req *DeleteClassRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteClass not implemented")
+}
+func (*UnimplementedCatalogServer) CreateProducts(ctx context.Context, req *CreateProductsRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateProducts not implemented")
+}
+func (*UnimplementedCatalogServer) GetProduct(ctx context.Context, req *GetProductRequest) (*Product, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProduct not implemented")
+}
+func (*UnimplementedCatalogServer) UpdateProduct(ctx context.Context, req *UpdateProductRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProduct not implemented")
+}
+func (*UnimplementedCatalogServer) DeleteProduct(ctx context.Context, req *DeleteProductRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteProduct not implemented")
+}
+func (*UnimplementedCatalogServer) ListProducts(req *ListProductsRequest, srv Catalog_ListProductsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListProducts not implemented")
+}
+
+func RegisterCatalogServer(s *grpc.Server, srv CatalogServer) {
+	s.RegisterService(&_Catalog_serviceDesc, srv)
+}
+
+func _Catalog_CreateEIs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateEIsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).CreateEIs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hsesql.catalog.Catalog/CreateEIs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).CreateEIs(ctx, req.(*CreateEIsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_ListEIs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEIsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).ListEIs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hsesql.catalog.Catalog/ListEIs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).ListEIs(ctx, req.(*ListEIsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_CreateValueTypes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateValueTypesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).CreateValueTypes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hsesql.catalog.Catalog/CreateValueTypes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).CreateValueTypes(ctx, req.(*CreateValueTypesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_ListValueTypes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListValueTypesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).ListValueTypes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hsesql.catalog.Catalog/ListValueTypes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).ListValueTypes(ctx, req.(*ListValueTypesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_CreateClasses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateClassesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).CreateClasses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hsesql.catalog.Catalog/CreateClasses",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).CreateClasses(ctx, req.(*CreateClassesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_GetClass_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetClassRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).GetClass(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hsesql.catalog.Catalog/GetClass",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).GetClass(ctx, req.(*GetClassRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_GetClassTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).GetClassTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hsesql.catalog.Catalog/GetClassTree",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).GetClassTree(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_DeleteClass_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteClassRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).DeleteClass(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hsesql.catalog.Catalog/DeleteClass",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).DeleteClass(ctx, req.(*DeleteClassRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_CreateProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).CreateProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hsesql.catalog.Catalog/CreateProducts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).CreateProducts(ctx, req.(*CreateProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_GetProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).GetProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hsesql.catalog.Catalog/GetProduct",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).GetProduct(ctx, req.(*GetProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_UpdateProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).UpdateProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hsesql.catalog.Catalog/UpdateProduct",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).UpdateProduct(ctx, req.(*UpdateProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_DeleteProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).DeleteProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hsesql.catalog.Catalog/DeleteProduct",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).DeleteProduct(ctx, req.(*DeleteProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_ListProducts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListProductsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CatalogServer).ListProducts(m, &catalogListProductsServer{stream})
+}
+
+type Catalog_ListProductsServer interface {
+	Send(*Product) error
+	grpc.ServerStream
+}
+
+type catalogListProductsServer struct {
+	grpc.ServerStream
+}
+
+func (x *catalogListProductsServer) Send(m *Product) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Catalog_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "hsesql.catalog.Catalog",
+	HandlerType: (*CatalogServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateEIs",
+			Handler:    _Catalog_CreateEIs_Handler,
+		},
+		{
+			MethodName: "ListEIs",
+			Handler:    _Catalog_ListEIs_Handler,
+		},
+		{
+			MethodName: "CreateValueTypes",
+			Handler:    _Catalog_CreateValueTypes_Handler,
+		},
+		{
+			MethodName: "ListValueTypes",
+			Handler:    _Catalog_ListValueTypes_Handler,
+		},
+		{
+			MethodName: "CreateClasses",
+			Handler:    _Catalog_CreateClasses_Handler,
+		},
+		{
+			MethodName: "GetClass",
+			Handler:    _Catalog_GetClass_Handler,
+		},
+		{
+			MethodName: "GetClassTree",
+			Handler:    _Catalog_GetClassTree_Handler,
+		},
+		{
+			MethodName: "DeleteClass",
+			Handler:    _Catalog_DeleteClass_Handler,
+		},
+		{
+			MethodName: "CreateProducts",
+			Handler:    _Catalog_CreateProducts_Handler,
+		},
+		{
+			MethodName: "GetProduct",
+			Handler:    _Catalog_GetProduct_Handler,
+		},
+		{
+			MethodName: "UpdateProduct",
+			Handler:    _Catalog_UpdateProduct_Handler,
+		},
+		{
+			MethodName: "DeleteProduct",
+			Handler:    _Catalog_DeleteProduct_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListProducts",
+			Handler:       _Catalog_ListProducts_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "catalog.proto",
+}
@@ -0,0 +1,4 @@
+// Package catalogpb keeps the code generated from api/catalog.proto
+package catalogpb
+
+//go:generate protoc -I ../../api --go_out=plugins=grpc,paths=source_relative:. catalog.proto
@@ -0,0 +1,130 @@
+package classification
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// bmecat is the part of a BMEcat 2005 catalog that publishes a classification system,
+// the products of the catalog are not read. The elements are matched in any namespace
+type bmecat struct {
+	Systems []*bmecatSystem `xml:"T_NEW_CATALOG>CLASSIFICATION_SYSTEM"`
+}
+
+type bmecatSystem struct {
+	Name      string            `xml:"CLASSIFICATION_SYSTEM_NAME"`
+	Version   string            `xml:"CLASSIFICATION_SYSTEM_VERSION"`
+	Values    []*bmecatValue    `xml:"ALLOWED_VALUES>ALLOWED_VALUE"`
+	Units     []*bmecatUnit     `xml:"UNITS>UNIT"`
+	Templates []*bmecatTemplate `xml:"CLASSIFICATION_SYSTEM_FEATURE_TEMPLATES>FEATURE_TEMPLATE"`
+	Groups    []*bmecatGroup    `xml:"CLASSIFICATION_GROUPS>CLASSIFICATION_GROUP"`
+}
+
+type bmecatValue struct {
+	Id    string   `xml:"ALLOWED_VALUE_ID"`
+	Names []string `xml:"ALLOWED_VALUE_NAME"`
+}
+
+type bmecatUnit struct {
+	Id         string   `xml:"UNIT_ID"`
+	Names      []string `xml:"UNIT_NAME"`
+	ShortNames []string `xml:"UNIT_SHORTNAME"`
+}
+
+type bmecatTemplate struct {
+	Id       string   `xml:"FT_ID"`
+	Names    []string `xml:"FT_NAME"`
+	DataType string   `xml:"FT_DATATYPE"`
+	Unit     string   `xml:"FT_UNIT_IDREF"`
+}
+
+type bmecatGroup struct {
+	Type      string                 `xml:"type,attr"`
+	Id        string                 `xml:"CLASSIFICATION_GROUP_ID"`
+	Names     []string               `xml:"CLASSIFICATION_GROUP_NAME"`
+	Parent    string                 `xml:"CLASSIFICATION_GROUP_PARENT_ID"`
+	Templates []*bmecatGroupTemplate `xml:"CLASSIFICATION_GROUP_FEATURE_TEMPLATES>CLASSIFICATION_GROUP_FEATURE_TEMPLATE"`
+}
+
+type bmecatGroupTemplate struct {
+	Id     string   `xml:"FT_IDREF"`
+	Unit   string   `xml:"FT_UNIT_IDREF"`
+	Values []string `xml:"FT_ALLOWED_VALUES>ALLOWED_VALUE_IDREF"`
+}
+
+// bmecatTypes maps the data types of the feature templates to the feature types
+var bmecatTypes = map[string]string{
+	"string":  TypeAlphanumeric,
+	"boolean": TypeLogical,
+	"integer": TypeNumeric,
+	"float":   TypeNumeric,
+	"number":  TypeNumeric,
+	"range":   TypeRange,
+}
+
+// release converts the only classification system of the catalog
+func (b *bmecat) release() (*Release, error) {
+	if len(b.Systems) != 1 {
+		return nil, Problems{fmt.Sprintf("the catalog has %d classification systems, one is expected", len(b.Systems))}
+	}
+	s := b.Systems[0]
+	rel := &Release{
+		// the name often carries the version, the codes of a system are kept across its versions
+		System:  strings.TrimSuffix(strings.TrimSpace(s.Name), "-"+strings.TrimSpace(s.Version)),
+		Release: strings.TrimSpace(s.Version),
+	}
+	for _, u := range s.Units {
+		rel.Units = append(rel.Units, &Unit{Code: u.Id, Name: first(u.Names), ShortName: first(u.ShortNames)})
+	}
+	for _, v := range s.Values {
+		rel.Values = append(rel.Values, &Value{Code: v.Id, Name: first(v.Names)})
+	}
+	for _, t := range s.Templates {
+		// the letters of ETIM are taken as they are, the unknown types are reported by the check
+		typ, ok := bmecatTypes[strings.ToLower(t.DataType)]
+		if !ok {
+			typ = t.DataType
+		}
+		rel.Features = append(rel.Features, &Feature{Code: t.Id, Type: typ, Unit: t.Unit, Name: first(t.Names)})
+	}
+	for _, g := range s.Groups {
+		c := &Class{Code: g.Id, Parent: g.Parent, Name: first(g.Names)}
+		for _, t := range g.Templates {
+			cf := &ClassFeature{Code: t.Id, Unit: t.Unit}
+			for _, v := range t.Values {
+				cf.Values = append(cf.Values, &ValueRef{Code: v})
+			}
+			c.Features = append(c.Features, cf)
+		}
+		if g.Type == "node" {
+			rel.Groups = append(rel.Groups, c)
+			continue
+		}
+		rel.Classes = append(rel.Classes, c)
+	}
+	return rel, nil
+}
+
+// first returns the first of the names given in several languages
+func first(names []string) string {
+	for _, n := range names {
+		if n = strings.TrimSpace(n); n != "" {
+			return n
+		}
+	}
+	return ""
+}
+
+// rootName reads the name of the root element, the decoder is left after its start
+func rootName(d *xml.Decoder) (xml.StartElement, error) {
+	for {
+		t, err := d.Token()
+		if err != nil {
+			return xml.StartElement{}, err
+		}
+		if start, ok := t.(xml.StartElement); ok {
+			return start, nil
+		}
+	}
+}
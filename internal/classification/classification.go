@@ -0,0 +1,289 @@
+// Package classification reads a release of a classification standard like ETIM or eCl@ss.
+// The standards publish their releases as BMEcat 2005 catalogs, whose CLASSIFICATION_SYSTEM
+// is read as it is: the units, the allowed values, the feature templates and the classification
+// groups with the feature templates they use. The standard codes identify all of them, so a newer
+// release of the same system can be matched against the one imported before:
+//
+//	<BMECAT version="2005" xmlns="http://www.bmecat.org/bmecat/2005">
+//	  <T_NEW_CATALOG>
+//	    <CLASSIFICATION_SYSTEM>
+//	      <CLASSIFICATION_SYSTEM_NAME>ETIM</CLASSIFICATION_SYSTEM_NAME>
+//	      <CLASSIFICATION_SYSTEM_VERSION>9.0</CLASSIFICATION_SYSTEM_VERSION>
+//	      <ALLOWED_VALUES>
+//	        <ALLOWED_VALUE><ALLOWED_VALUE_ID>EV000080</ALLOWED_VALUE_ID><ALLOWED_VALUE_NAME>Red</ALLOWED_VALUE_NAME></ALLOWED_VALUE>
+//	      </ALLOWED_VALUES>
+//	      <UNITS>
+//	        <UNIT><UNIT_ID>EU570448</UNIT_ID><UNIT_NAME>millimetre</UNIT_NAME><UNIT_SHORTNAME>mm</UNIT_SHORTNAME></UNIT>
+//	      </UNITS>
+//	      <CLASSIFICATION_SYSTEM_FEATURE_TEMPLATES>
+//	        <FEATURE_TEMPLATE><FT_ID>EF000008</FT_ID><FT_NAME>Width</FT_NAME><FT_DATATYPE>float</FT_DATATYPE>
+//	          <FT_UNIT_IDREF>EU570448</FT_UNIT_IDREF></FEATURE_TEMPLATE>
+//	      </CLASSIFICATION_SYSTEM_FEATURE_TEMPLATES>
+//	      <CLASSIFICATION_GROUPS>
+//	        <CLASSIFICATION_GROUP type="node">
+//	          <CLASSIFICATION_GROUP_ID>EG000017</CLASSIFICATION_GROUP_ID><CLASSIFICATION_GROUP_NAME>Cables</CLASSIFICATION_GROUP_NAME>
+//	        </CLASSIFICATION_GROUP>
+//	        <CLASSIFICATION_GROUP type="leaf">
+//	          <CLASSIFICATION_GROUP_ID>EC000001</CLASSIFICATION_GROUP_ID><CLASSIFICATION_GROUP_NAME>Cable</CLASSIFICATION_GROUP_NAME>
+//	          <CLASSIFICATION_GROUP_FEATURE_TEMPLATES>
+//	            <CLASSIFICATION_GROUP_FEATURE_TEMPLATE><FT_IDREF>EF000008</FT_IDREF></CLASSIFICATION_GROUP_FEATURE_TEMPLATE>
+//	          </CLASSIFICATION_GROUP_FEATURE_TEMPLATES>
+//	          <CLASSIFICATION_GROUP_PARENT_ID>EG000017</CLASSIFICATION_GROUP_PARENT_ID>
+//	        </CLASSIFICATION_GROUP>
+//	      </CLASSIFICATION_GROUPS>
+//	    </CLASSIFICATION_SYSTEM>
+//	  </T_NEW_CATALOG>
+//	</BMECAT>
+//
+// The string templates are alphanumeric, the boolean ones are logical, the integer and float ones are
+// numeric and the range ones are range features, the ETIM letters are taken as they are. An ETIM release
+// keeps the codes of its units (EU), features (EF), values (EV), groups (EG) and classes (EC), an eCl@ss
+// release the IRDIs. The first name of the ones given in several languages is used, and a version
+// at the end of the system name is dropped, so the releases of a system share their codes.
+//
+// The same release can also be given in the CLASSIFICATION layout the catalog uses internally,
+// which the tests and the hand made classifications use:
+//
+//	<CLASSIFICATION system="ETIM" release="9.0">
+//	  <UNITS><UNIT code="EU570448"><NAME>millimetre</NAME><SHORT_NAME>mm</SHORT_NAME></UNIT></UNITS>
+//	  <FEATURES><FEATURE code="EF000008" type="N" unit="EU570448"><NAME>Width</NAME></FEATURE></FEATURES>
+//	  <VALUES><VALUE code="EV000080"><NAME>Red</NAME></VALUE></VALUES>
+//	  <GROUPS><GROUP code="EG000017"><NAME>Cables</NAME></GROUP></GROUPS>
+//	  <CLASSES>
+//	    <CLASS code="EC000001" group="EG000017"><NAME>Cable</NAME>
+//	      <FEATURE code="EF000008"/>
+//	      <FEATURE code="EF000007"><VALUE code="EV000080"/></FEATURE>
+//	    </CLASS>
+//	  </CLASSES>
+//	</CLASSIFICATION>
+//
+// Groups are classes without features, a class is put under its group or under the parent class
+package classification
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// types of the features, the letters are the ones of ETIM
+const (
+	// TypeAlphanumeric is a text or a choice of the listed values
+	TypeAlphanumeric = "A"
+	TypeLogical      = "L"
+	TypeNumeric      = "N"
+	// TypeRange is a pair of numbers, the catalog has no params for it
+	TypeRange = "R"
+)
+
+type Release struct {
+	XMLName  xml.Name   `xml:"CLASSIFICATION"`
+	System   string     `xml:"system,attr"`
+	Release  string     `xml:"release,attr"`
+	Units    []*Unit    `xml:"UNITS>UNIT"`
+	Features []*Feature `xml:"FEATURES>FEATURE"`
+	Values   []*Value   `xml:"VALUES>VALUE"`
+	Groups   []*Class   `xml:"GROUPS>GROUP"`
+	Classes  []*Class   `xml:"CLASSES>CLASS"`
+}
+
+type Unit struct {
+	Code      string `xml:"code,attr"`
+	Name      string `xml:"NAME"`
+	ShortName string `xml:"SHORT_NAME"`
+}
+
+type Feature struct {
+	Code string `xml:"code,attr"`
+	Type string `xml:"type,attr"`
+	// Unit is the code of the unit of the numeric values
+	Unit string `xml:"unit,attr"`
+	Name string `xml:"NAME"`
+}
+
+type Value struct {
+	Code string `xml:"code,attr"`
+	Name string `xml:"NAME"`
+}
+
+type Class struct {
+	Code   string `xml:"code,attr"`
+	Group  string `xml:"group,attr"`
+	Parent string `xml:"parent,attr"`
+	Name   string `xml:"NAME"`
+	// Features are the ones the class adds to the features of its parents
+	Features []*ClassFeature `xml:"FEATURE"`
+}
+
+// ClassFeature attaches the feature to the class, the values of an alphanumeric
+// feature are the ones the products of the class can have
+type ClassFeature struct {
+	Code   string      `xml:"code,attr"`
+	Unit   string      `xml:"unit,attr"`
+	Values []*ValueRef `xml:"VALUE"`
+}
+
+type ValueRef struct {
+	Code string `xml:"code,attr"`
+}
+
+// ParentCode is the code of the group or the class the class is put under, empty for a root
+func (c *Class) ParentCode() string {
+	if c.Parent != "" {
+		return c.Parent
+	}
+	return c.Group
+}
+
+// Problems lists everything that is wrong with the release at once
+type Problems []string
+
+func (p Problems) Error() string {
+	return "invalid classification: " + strings.Join(p, "; ")
+}
+
+// Read decodes the release from a BMEcat catalog or from the CLASSIFICATION layout
+// and checks that the codes it refers to are defined
+func Read(r io.Reader) (*Release, error) {
+	d := xml.NewDecoder(r)
+	root, err := rootName(d)
+	if err != nil {
+		return nil, err
+	}
+	var rel *Release
+	switch root.Name.Local {
+	case "BMECAT":
+		b := &bmecat{}
+		if err := d.DecodeElement(b, &root); err != nil {
+			return nil, err
+		}
+		if rel, err = b.release(); err != nil {
+			return nil, err
+		}
+	case "CLASSIFICATION":
+		rel = &Release{}
+		if err := d.DecodeElement(rel, &root); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown root element %s, expected BMECAT or CLASSIFICATION", root.Name.Local)
+	}
+	if err := rel.check(); err != nil {
+		return nil, err
+	}
+	return rel, nil
+}
+
+func (rel *Release) check() error {
+	var problems Problems
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+	if strings.TrimSpace(rel.System) == "" {
+		add("the system is missing")
+	}
+	units := make(map[string]bool)
+	for _, u := range rel.Units {
+		switch {
+		case u.Code == "" || u.Name == "":
+			add("unit %q has no code or name", u.Code+u.Name)
+		case units[u.Code]:
+			add("unit %s is defined twice", u.Code)
+		}
+		units[u.Code] = true
+	}
+	features := make(map[string]bool)
+	for _, f := range rel.Features {
+		switch {
+		case f.Code == "" || f.Name == "":
+			add("feature %q has no code or name", f.Code+f.Name)
+		case features[f.Code]:
+			add("feature %s is defined twice", f.Code)
+		}
+		features[f.Code] = true
+		switch f.Type {
+		case TypeAlphanumeric, TypeLogical, TypeNumeric, TypeRange:
+		default:
+			add("feature %s has unknown type %q", f.Code, f.Type)
+		}
+		if f.Unit != "" && !units[f.Unit] {
+			add("feature %s refers to unknown unit %s", f.Code, f.Unit)
+		}
+	}
+	values := make(map[string]bool)
+	for _, v := range rel.Values {
+		switch {
+		case v.Code == "" || v.Name == "":
+			add("value %q has no code or name", v.Code+v.Name)
+		case values[v.Code]:
+			add("value %s is defined twice", v.Code)
+		}
+		values[v.Code] = true
+	}
+	classes := make(map[string]*Class)
+	for _, c := range rel.AllClasses() {
+		switch {
+		case c.Code == "" || c.Name == "":
+			add("class %q has no code or name", c.Code+c.Name)
+		case classes[c.Code] != nil:
+			add("class %s is defined twice", c.Code)
+		}
+		classes[c.Code] = c
+	}
+	for _, c := range rel.AllClasses() {
+		if parent := c.ParentCode(); parent != "" && classes[parent] == nil {
+			add("class %s refers to unknown parent %s", c.Code, parent)
+		}
+		for _, cf := range c.Features {
+			if !features[cf.Code] {
+				add("class %s refers to unknown feature %s", c.Code, cf.Code)
+			}
+			if cf.Unit != "" && !units[cf.Unit] {
+				add("class %s refers to unknown unit %s", c.Code, cf.Unit)
+			}
+			for _, v := range cf.Values {
+				if !values[v.Code] {
+					add("class %s refers to unknown value %s", c.Code, v.Code)
+				}
+			}
+		}
+	}
+	if len(problems) == 0 {
+		if _, err := rel.Tree(); err != nil {
+			return err
+		}
+	}
+	if len(problems) != 0 {
+		return problems
+	}
+	return nil
+}
+
+// AllClasses returns the groups and then the classes
+func (rel *Release) AllClasses() []*Class {
+	all := make([]*Class, 0, len(rel.Groups)+len(rel.Classes))
+	all = append(all, rel.Groups...)
+	return append(all, rel.Classes...)
+}
+
+// Tree orders the groups and the classes so every parent comes before its children
+func (rel *Release) Tree() ([]*Class, error) {
+	all := rel.AllClasses()
+	children := make(map[string][]*Class)
+	for _, c := range all {
+		children[c.ParentCode()] = append(children[c.ParentCode()], c)
+	}
+	ordered := make([]*Class, 0, len(all))
+	queue := children[""]
+	for len(queue) != 0 {
+		c := queue[0]
+		queue = queue[1:]
+		ordered = append(ordered, c)
+		queue = append(queue, children[c.Code]...)
+	}
+	if len(ordered) != len(all) {
+		return nil, Problems{"the parents of some classes form a cycle"}
+	}
+	return ordered, nil
+}
@@ -0,0 +1,217 @@
+package classification
+
+import (
+	"strings"
+	"testing"
+)
+
+const release = `<CLASSIFICATION system="ETIM" release="9.0">
+	<UNITS><UNIT code="EU570448"><NAME>millimetre</NAME><SHORT_NAME>mm</SHORT_NAME></UNIT></UNITS>
+	<FEATURES>
+		<FEATURE code="EF000008" type="N" unit="EU570448"><NAME>Width</NAME></FEATURE>
+		<FEATURE code="EF000007" type="A"><NAME>Colour</NAME></FEATURE>
+	</FEATURES>
+	<VALUES><VALUE code="EV000080"><NAME>Red</NAME></VALUE></VALUES>
+	<GROUPS><GROUP code="EG000017"><NAME>Cables</NAME></GROUP></GROUPS>
+	<CLASSES>
+		<CLASS code="EC000002" parent="EC000001"><NAME>Power cable</NAME></CLASS>
+		<CLASS code="EC000001" group="EG000017"><NAME>Cable</NAME>
+			<FEATURE code="EF000008"/>
+			<FEATURE code="EF000007"><VALUE code="EV000080"/></FEATURE>
+		</CLASS>
+	</CLASSES>
+</CLASSIFICATION>`
+
+func TestRead(t *testing.T) {
+	rel, err := Read(strings.NewReader(release))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rel.System != "ETIM" || rel.Release != "9.0" {
+		t.Errorf("system %q release %q", rel.System, rel.Release)
+	}
+	tree, err := rel.Tree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var codes []string
+	for _, c := range tree {
+		codes = append(codes, c.Code)
+	}
+	if got, want := strings.Join(codes, " "), "EG000017 EC000001 EC000002"; got != want {
+		t.Errorf("tree order %s, want %s", got, want)
+	}
+}
+
+const catalog = `<?xml version="1.0" encoding="UTF-8"?>
+<BMECAT version="2005" xmlns="http://www.bmecat.org/bmecat/2005">
+	<HEADER><CATALOG><LANGUAGE>eng</LANGUAGE></CATALOG></HEADER>
+	<T_NEW_CATALOG>
+		<CLASSIFICATION_SYSTEM>
+			<CLASSIFICATION_SYSTEM_NAME>ETIM-9.0</CLASSIFICATION_SYSTEM_NAME>
+			<CLASSIFICATION_SYSTEM_VERSION>9.0</CLASSIFICATION_SYSTEM_VERSION>
+			<ALLOWED_VALUES>
+				<ALLOWED_VALUE><ALLOWED_VALUE_ID>EV000080</ALLOWED_VALUE_ID>
+					<ALLOWED_VALUE_NAME lang="eng">Red</ALLOWED_VALUE_NAME><ALLOWED_VALUE_NAME lang="deu">Rot</ALLOWED_VALUE_NAME>
+				</ALLOWED_VALUE>
+			</ALLOWED_VALUES>
+			<UNITS>
+				<UNIT><UNIT_ID>EU570448</UNIT_ID><UNIT_NAME>millimetre</UNIT_NAME><UNIT_SHORTNAME>mm</UNIT_SHORTNAME></UNIT>
+			</UNITS>
+			<CLASSIFICATION_SYSTEM_FEATURE_TEMPLATES>
+				<FEATURE_TEMPLATE><FT_ID>EF000008</FT_ID><FT_NAME>Width</FT_NAME><FT_DATATYPE>float</FT_DATATYPE>
+					<FT_UNIT_IDREF>EU570448</FT_UNIT_IDREF></FEATURE_TEMPLATE>
+				<FEATURE_TEMPLATE><FT_ID>EF000007</FT_ID><FT_NAME>Colour</FT_NAME><FT_DATATYPE>A</FT_DATATYPE></FEATURE_TEMPLATE>
+			</CLASSIFICATION_SYSTEM_FEATURE_TEMPLATES>
+			<CLASSIFICATION_GROUPS>
+				<CLASSIFICATION_GROUP type="leaf">
+					<CLASSIFICATION_GROUP_ID>EC000001</CLASSIFICATION_GROUP_ID>
+					<CLASSIFICATION_GROUP_NAME>Cable</CLASSIFICATION_GROUP_NAME>
+					<CLASSIFICATION_GROUP_FEATURE_TEMPLATES>
+						<CLASSIFICATION_GROUP_FEATURE_TEMPLATE><FT_IDREF>EF000008</FT_IDREF></CLASSIFICATION_GROUP_FEATURE_TEMPLATE>
+						<CLASSIFICATION_GROUP_FEATURE_TEMPLATE><FT_IDREF>EF000007</FT_IDREF>
+							<FT_ALLOWED_VALUES><ALLOWED_VALUE_IDREF>EV000080</ALLOWED_VALUE_IDREF></FT_ALLOWED_VALUES>
+						</CLASSIFICATION_GROUP_FEATURE_TEMPLATE>
+					</CLASSIFICATION_GROUP_FEATURE_TEMPLATES>
+					<CLASSIFICATION_GROUP_PARENT_ID>EG000017</CLASSIFICATION_GROUP_PARENT_ID>
+				</CLASSIFICATION_GROUP>
+				<CLASSIFICATION_GROUP type="node">
+					<CLASSIFICATION_GROUP_ID>EG000017</CLASSIFICATION_GROUP_ID>
+					<CLASSIFICATION_GROUP_NAME>Cables</CLASSIFICATION_GROUP_NAME>
+				</CLASSIFICATION_GROUP>
+			</CLASSIFICATION_GROUPS>
+		</CLASSIFICATION_SYSTEM>
+	</T_NEW_CATALOG>
+</BMECAT>`
+
+func TestReadBMEcat(t *testing.T) {
+	rel, err := Read(strings.NewReader(catalog))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rel.System != "ETIM" || rel.Release != "9.0" {
+		t.Errorf("system %q release %q", rel.System, rel.Release)
+	}
+	if len(rel.Features) != 2 || rel.Features[0].Type != TypeNumeric || rel.Features[0].Unit != "EU570448" ||
+		rel.Features[1].Type != TypeAlphanumeric {
+		t.Errorf("features %+v %+v", rel.Features[0], rel.Features[1])
+	}
+	if len(rel.Values) != 1 || rel.Values[0].Name != "Red" {
+		t.Errorf("values %+v", rel.Values)
+	}
+	if len(rel.Units) != 1 || rel.Units[0].ShortName != "mm" {
+		t.Errorf("units %+v", rel.Units)
+	}
+	tree, err := rel.Tree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tree) != 2 || tree[0].Code != "EG000017" || tree[1].Code != "EC000001" {
+		t.Fatalf("tree %+v", tree)
+	}
+	if cf := tree[1].Features; len(cf) != 2 || len(cf[1].Values) != 1 || cf[1].Values[0].Code != "EV000080" {
+		t.Errorf("class features %+v", cf)
+	}
+}
+
+func TestReadUnknownRoot(t *testing.T) {
+	if _, err := Read(strings.NewReader(`<CATALOG/>`)); err == nil {
+		t.Error("unknown root is accepted")
+	}
+}
+
+func TestReadProblems(t *testing.T) {
+	tests := []struct {
+		name, xml string
+		want      []string
+	}{
+		{
+			name: "missing system",
+			xml:  `<CLASSIFICATION release="1"></CLASSIFICATION>`,
+			want: []string{"the system is missing"},
+		},
+		{
+			name: "unknown parent",
+			xml: `<CLASSIFICATION system="S"><CLASSES>
+				<CLASS code="C1" parent="C0"><NAME>One</NAME></CLASS>
+			</CLASSES></CLASSIFICATION>`,
+			want: []string{"class C1 refers to unknown parent C0"},
+		},
+		{
+			name: "unknown group",
+			xml: `<CLASSIFICATION system="S"><CLASSES>
+				<CLASS code="C1" group="G1"><NAME>One</NAME></CLASS>
+			</CLASSES></CLASSIFICATION>`,
+			want: []string{"class C1 refers to unknown parent G1"},
+		},
+		{
+			name: "dangling references of a class",
+			xml: `<CLASSIFICATION system="S">
+				<FEATURES><FEATURE code="F1" type="A"><NAME>F</NAME></FEATURE></FEATURES>
+				<CLASSES><CLASS code="C1"><NAME>One</NAME>
+					<FEATURE code="F2"/>
+					<FEATURE code="F1" unit="U1"><VALUE code="V1"/></FEATURE>
+				</CLASS></CLASSES>
+			</CLASSIFICATION>`,
+			want: []string{
+				"class C1 refers to unknown feature F2",
+				"class C1 refers to unknown unit U1",
+				"class C1 refers to unknown value V1",
+			},
+		},
+		{
+			name: "unknown unit and type of a feature",
+			xml: `<CLASSIFICATION system="S"><FEATURES>
+				<FEATURE code="F1" type="X" unit="U1"><NAME>F</NAME></FEATURE>
+			</FEATURES></CLASSIFICATION>`,
+			want: []string{`feature F1 has unknown type "X"`, "feature F1 refers to unknown unit U1"},
+		},
+		{
+			name: "duplicates",
+			xml: `<CLASSIFICATION system="S">
+				<VALUES><VALUE code="V1"><NAME>A</NAME></VALUE><VALUE code="V1"><NAME>B</NAME></VALUE></VALUES>
+				<GROUPS><GROUP code="C1"><NAME>G</NAME></GROUP></GROUPS>
+				<CLASSES><CLASS code="C1"><NAME>C</NAME></CLASS></CLASSES>
+			</CLASSIFICATION>`,
+			want: []string{"value V1 is defined twice", "class C1 is defined twice"},
+		},
+		{
+			name: "cycle",
+			xml: `<CLASSIFICATION system="S"><CLASSES>
+				<CLASS code="C1" parent="C2"><NAME>One</NAME></CLASS>
+				<CLASS code="C2" parent="C1"><NAME>Two</NAME></CLASS>
+			</CLASSES></CLASSIFICATION>`,
+			want: []string{"the parents of some classes form a cycle"},
+		},
+		{
+			name: "cycle under a root",
+			xml: `<CLASSIFICATION system="S"><CLASSES>
+				<CLASS code="C0"><NAME>Root</NAME></CLASS>
+				<CLASS code="C1" parent="C1"><NAME>Self</NAME></CLASS>
+			</CLASSES></CLASSIFICATION>`,
+			want: []string{"the parents of some classes form a cycle"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(strings.NewReader(tt.xml))
+			problems, ok := err.(Problems)
+			if !ok {
+				t.Fatalf("got %v, want problems", err)
+			}
+			if got, want := strings.Join(problems, "; "), strings.Join(tt.want, "; "); got != want {
+				t.Errorf("got %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestReadBrokenXml(t *testing.T) {
+	_, err := Read(strings.NewReader(`<CLASSIFICATION system="S"><CLASSES>`))
+	if err == nil {
+		t.Fatal("broken xml is accepted")
+	}
+	if _, ok := err.(Problems); ok {
+		t.Errorf("broken xml is reported as problems: %v", err)
+	}
+}
@@ -0,0 +1,351 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"github.com/jackc/pgx/v4"
+	"hseSQL/internal"
+)
+
+// BATCHED READS
+// these read many classes or products at once, so nested queries over the class tree
+// take a query per level instead of a query per class
+
+// ClassNode is a class with the ids of its parent and children, IdParent is zero for a root
+type ClassNode struct {
+	Class      *internal.Class
+	IdParent   int
+	IdChildren []int
+}
+
+func (do *DbOperator) ReadRootClassIds() ([]int, error) {
+	var ids []int
+	f := func(tx pgx.Tx) error {
+		rows, err := tx.Query(context.Background(),
+			`SELECT ID_CLASS
+				FROM CLASSES
+				WHERE ID_PARENT_CLASS IS NULL
+				ORDER BY ID_CLASS`)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		var id int
+		for rows.Next() {
+			if err := rows.Scan(&id); err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+		return rows.Err()
+	}
+	return ids, do.cs.WrapIntoTransaction(context.Background(), f)
+}
+
+// ReadClassNodes reads the classes by ids without their params, missing classes are not in the result
+func (do *DbOperator) ReadClassNodes(ids []int) (map[int]*ClassNode, error) {
+	res := make(map[int]*ClassNode)
+	f := func(tx pgx.Tx) error {
+		rows, err := tx.Query(context.Background(),
+			`SELECT C.ID_CLASS, C.NAME, C.ID_PARENT_CLASS, EI.NAME, EI.SHORT_NAME
+				FROM CLASSES C LEFT JOIN EI ON C.ID_EI = EI.ID_EI
+				WHERE C.ID_CLASS = ANY($1)`,
+			ids)
+		if err != nil {
+			return err
+		}
+		var id int
+		var idParent sql.NullInt32
+		var name string
+		var eiName, eiShortName sql.NullString
+		for rows.Next() {
+			if err := rows.Scan(&id, &name, &idParent, &eiName, &eiShortName); err != nil {
+				rows.Close()
+				return err
+			}
+			c := &internal.Class{
+				Id:     id,
+				Name:   name,
+				Params: []*internal.Param{},
+			}
+			if eiName.Valid {
+				c.Ei = &internal.EI{Name: eiName.String, ShortName: eiShortName.String}
+			}
+			res[id] = &ClassNode{Class: c, IdParent: int(idParent.Int32), IdChildren: []int{}}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		rows, err = tx.Query(context.Background(),
+			`SELECT ID_CLASS, ID_PARENT_CLASS
+				FROM CLASSES
+				WHERE ID_PARENT_CLASS = ANY($1)
+				ORDER BY ID_CLASS`,
+			ids)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		var idChild int
+		for rows.Next() {
+			if err := rows.Scan(&idChild, &id); err != nil {
+				return err
+			}
+			res[id].IdChildren = append(res[id].IdChildren, idChild)
+		}
+		return rows.Err()
+	}
+	return res, do.cs.WrapIntoTransaction(context.Background(), f)
+}
+
+// ReadAncestorIds returns the ancestors of each class from the root down to the parent
+func (do *DbOperator) ReadAncestorIds(ids []int) (map[int][]int, error) {
+	res := make(map[int][]int)
+	f := func(tx pgx.Tx) error {
+		rows, err := tx.Query(context.Background(),
+			`WITH RECURSIVE ANCESTORS AS (
+					SELECT ID_CLASS AS ID_ORIGIN, ID_PARENT_CLASS AS ID_ANCESTOR, 1 AS DEPTH
+					FROM CLASSES
+					WHERE ID_CLASS = ANY($1) AND ID_PARENT_CLASS IS NOT NULL UNION ALL
+					SELECT A.ID_ORIGIN, C.ID_PARENT_CLASS, A.DEPTH + 1
+					FROM CLASSES C INNER JOIN ANCESTORS A ON A.ID_ANCESTOR = C.ID_CLASS
+					WHERE C.ID_PARENT_CLASS IS NOT NULL)
+				SELECT ID_ORIGIN, ID_ANCESTOR
+				FROM ANCESTORS
+				ORDER BY ID_ORIGIN, DEPTH DESC`,
+			ids)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		var id, idAncestor int
+		for rows.Next() {
+			if err := rows.Scan(&id, &idAncestor); err != nil {
+				return err
+			}
+			res[id] = append(res[id], idAncestor)
+		}
+		return rows.Err()
+	}
+	return res, do.cs.WrapIntoTransaction(context.Background(), f)
+}
+
+// ReadClassesParams returns the effective params of each class: its own ones, the inherited ones
+// and the overrides of the class and its ancestors applied to them, like r_Class does for one class
+func (do *DbOperator) ReadClassesParams(ids []int) (map[int][]*internal.Param, error) {
+	var res map[int][]*internal.Param
+	f := func(tx pgx.Tx) (err error) {
+		res, err = do.r_ClassesParams(tx, ids)
+		return
+	}
+	return res, do.cs.WrapIntoTransaction(context.Background(), f)
+}
+
+func (do *DbOperator) r_ClassesParams(tx pgx.Tx, ids []int) (map[int][]*internal.Param, error) {
+	res := make(map[int][]*internal.Param)
+	rows, err := tx.Query(context.Background(),
+		`WITH RECURSIVE FAMILY AS (
+				SELECT ID_CLASS AS ID_ORIGIN, ID_CLASS, ID_PARENT_CLASS, 0 AS DEPTH
+				FROM CLASSES
+				WHERE ID_CLASS = ANY($1) UNION ALL
+				SELECT F.ID_ORIGIN, C.ID_CLASS, C.ID_PARENT_CLASS, F.DEPTH + 1
+				FROM CLASSES C INNER JOIN FAMILY F ON F.ID_PARENT_CLASS = C.ID_CLASS)
+			SELECT F.ID_ORIGIN, P.ID_PARAM, P.NAME, VT.NAME, VT.BASE_TYPE, EIP.NAME, EIP.SHORT_NAME, CP.ID_CLASS,
+				CP.MIN_VALUE::FLOAT8, CP.MAX_VALUE::FLOAT8, CP.STEP::FLOAT8, CP.PRECISION, CP.MAX_LENGTH,
+				CP.REQUIRED, CP.DEFAULT_VALUE::TEXT
+			FROM CLASS_PARAMS CP JOIN FAMILY F ON F.ID_CLASS = CP.ID_CLASS
+							JOIN PARAMS P ON CP.ID_PARAM = P.ID_PARAM
+							JOIN VALUE_TYPES VT ON P.ID_VALUE_TYPE = VT.ID_VALUE_TYPE
+							JOIN EI EIP ON P.ID_EI = EIP.ID_EI
+			ORDER BY F.ID_ORIGIN, F.DEPTH DESC, CP.ID_CLASS_PARAM`,
+		ids)
+	if err != nil {
+		return nil, err
+	}
+	params := make(map[int]map[int]*internal.Param)
+	var idOrigin int
+	for rows.Next() {
+		p := &internal.Param{EI: &internal.EI{}}
+		constraints := &internal.ParamConstraints{}
+		var defaultValue sql.NullString
+		if err := rows.Scan(&idOrigin, &p.Id, &p.Name, &p.ValType, &p.BaseType, &p.EI.Name, &p.EI.ShortName,
+			&p.IdParamOwner, &constraints.Min, &constraints.Max, &constraints.Step, &constraints.Precision,
+			&constraints.MaxLength, &p.Required, &defaultValue); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		p.Constraints = constraints
+		if p.Default, err = decodeDefault(defaultValue); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		res[idOrigin] = append(res[idOrigin], p)
+		if params[idOrigin] == nil {
+			params[idOrigin] = make(map[int]*internal.Param)
+		}
+		params[idOrigin][p.Id] = p
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows, err = tx.Query(context.Background(),
+		`WITH RECURSIVE FAMILY AS (
+				SELECT ID_CLASS AS ID_ORIGIN, ID_CLASS, ID_PARENT_CLASS, 0 AS DEPTH
+				FROM CLASSES
+				WHERE ID_CLASS = ANY($1) UNION ALL
+				SELECT F.ID_ORIGIN, C.ID_CLASS, C.ID_PARENT_CLASS, F.DEPTH + 1
+				FROM CLASSES C INNER JOIN FAMILY F ON F.ID_PARENT_CLASS = C.ID_CLASS)
+			SELECT F.ID_ORIGIN, CP.ID_PARAM, CPO.MIN_VALUE::FLOAT8, CPO.MAX_VALUE::FLOAT8, CPO.STEP::FLOAT8,
+				CPO.PRECISION, CPO.MAX_LENGTH, CPO.REQUIRED, CPO.DEFAULT_VALUE::TEXT, EI.NAME, EI.SHORT_NAME
+			FROM CLASS_PARAM_OVERRIDES CPO JOIN FAMILY F ON F.ID_CLASS = CPO.ID_CLASS
+							JOIN CLASS_PARAMS CP ON CP.ID_CLASS_PARAM = CPO.ID_CLASS_PARAM
+							LEFT JOIN EI ON EI.ID_EI = CPO.ID_DISPLAY_EI
+			ORDER BY F.ID_ORIGIN, F.DEPTH DESC`,
+		ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var idParam int
+	for rows.Next() {
+		var defaultValue, eiName, eiShortName sql.NullString
+		o := &internal.ParamOverride{Constraints: &internal.ParamConstraints{}}
+		if err := rows.Scan(&idOrigin, &idParam, &o.Constraints.Min, &o.Constraints.Max, &o.Constraints.Step,
+			&o.Constraints.Precision, &o.Constraints.MaxLength, &o.Required, &defaultValue,
+			&eiName, &eiShortName); err != nil {
+			return nil, err
+		}
+		if o.Default, err = decodeDefault(defaultValue); err != nil {
+			return nil, err
+		}
+		if eiName.Valid {
+			o.DisplayEI = &internal.EI{Name: eiName.String, ShortName: eiShortName.String}
+		}
+		if p, ok := params[idOrigin][idParam]; ok {
+			applyOverride(p, o)
+		}
+	}
+	return res, rows.Err()
+}
+
+// r_Classes reads the classes by ids without their params like r_Class does, missing classes are not in the result
+func (do *DbOperator) r_Classes(tx pgx.Tx, ids []int) (map[int]*internal.Class, error) {
+	rows, err := tx.Query(context.Background(),
+		`SELECT C.ID_CLASS, C.NAME, EIC.NAME, EIC.SHORT_NAME, C.VERSION
+			FROM CLASSES C JOIN EI EIC ON C.ID_EI = EIC.ID_EI
+			WHERE C.ID_CLASS = ANY($1)`,
+		ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := make(map[int]*internal.Class)
+	for rows.Next() {
+		c := &internal.Class{Ei: &internal.EI{}, Params: []*internal.Param{}}
+		if err := rows.Scan(&c.Id, &c.Name, &c.Ei.Name, &c.Ei.ShortName, &c.Version); err != nil {
+			return nil, err
+		}
+		res[c.Id] = c
+	}
+	return res, rows.Err()
+}
+
+// ReadClassesProducts reads the same page of products for each class along with the total
+// numbers of their products. Only the id and name sort keys are supported
+func (do *DbOperator) ReadClassesProducts(ids []int, unit string, page *Page) (
+	map[int][]*internal.Product, map[int]int, error) {
+	res := make(map[int][]*internal.Product)
+	totals := make(map[int]int)
+	f := func(tx pgx.Tx) error {
+		order, err := page.orderBy(map[string]string{
+			"id":   "ID_PRODUCT",
+			"name": "NAME",
+		}, "id")
+		if err != nil {
+			return err
+		}
+		bounds := "RN > $2"
+		args := []interface{}{ids, 0}
+		if page != nil {
+			args[1] = page.Offset
+			if page.Limit > 0 {
+				bounds += " AND RN <= $3"
+				args = append(args, page.Offset+page.Limit)
+			}
+		}
+		rows, err := tx.Query(context.Background(),
+			`SELECT ID_PRODUCT
+				FROM (
+					SELECT ID_PRODUCT, ID_PARENT_CLASS,
+						ROW_NUMBER() OVER (PARTITION BY ID_PARENT_CLASS`+order+`) AS RN
+					FROM PRODUCTS
+					WHERE ID_PARENT_CLASS = ANY($1)) P
+				WHERE `+bounds+`
+				ORDER BY ID_PARENT_CLASS, RN`,
+			args...)
+		if err != nil {
+			return err
+		}
+		var productIds []int
+		var id int
+		for rows.Next() {
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			productIds = append(productIds, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		pp, err := do.r_Products(tx, productIds)
+		if err != nil {
+			return err
+		}
+		if err := do.convertProducts(tx, pp, unit); err != nil {
+			return err
+		}
+		for _, p := range pp {
+			res[p.ParentClass.Id] = append(res[p.ParentClass.Id], p)
+		}
+		rows, err = tx.Query(context.Background(),
+			`SELECT ID_PARENT_CLASS, COUNT(*)
+				FROM PRODUCTS
+				WHERE ID_PARENT_CLASS = ANY($1)
+				GROUP BY ID_PARENT_CLASS`,
+			ids)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		var total int
+		for rows.Next() {
+			if err := rows.Scan(&id, &total); err != nil {
+				return err
+			}
+			totals[id] = total
+		}
+		return rows.Err()
+	}
+	return res, totals, do.cs.WrapIntoTransaction(context.Background(), f)
+}
+
+// ReadProducts reads the products by ids in their order, missing products are skipped
+func (do *DbOperator) ReadProducts(ids []int, unit string) ([]*internal.Product, error) {
+	var res []*internal.Product
+	f := func(tx pgx.Tx) error {
+		pp, err := do.r_Products(tx, ids)
+		if err != nil {
+			return err
+		}
+		if err := do.convertProducts(tx, pp, unit); err != nil {
+			return err
+		}
+		res = pp
+		return nil
+	}
+	return res, do.cs.WrapIntoTransaction(context.Background(), f)
+}
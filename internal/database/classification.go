@@ -0,0 +1,577 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"hseSQL/internal"
+	"hseSQL/internal/classification"
+)
+
+// DefaultClassificationUnit is the ei of the imported classes and of the params that have no unit
+const DefaultClassificationUnit = "piece"
+
+// kinds of the items the codes of a classification are kept for
+const (
+	codeUnit    = "unit"
+	codeFeature = "feature"
+	codeValue   = "value"
+	codeClass   = "class"
+)
+
+// classificationValues counts the enum values in the report, the other kinds are the ones of the dump
+const classificationValues = "values"
+
+// ClassificationReport counts the items of the catalog the release created or changed by the kind
+type ClassificationReport struct {
+	System  string         `json:"system"`
+	Release string         `json:"release"`
+	Created map[string]int `json:"created"`
+	Updated map[string]int `json:"updated"`
+	// Warnings tell where the catalog was left as it is, as it can't follow the release there
+	Warnings []string `json:"warnings"`
+}
+
+func (r *ClassificationReport) warn(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// classificationImport keeps what the steps of the import learned about the release
+type classificationImport struct {
+	do     *DbOperator
+	tx     pgx.Tx
+	rel    *classification.Release
+	report *ClassificationReport
+	// unit is the name of the ei of the classes and of the params without a unit
+	unit string
+	// the ei names, the params and the class ids by the codes of the release
+	units   map[string]string
+	params  map[string]*internal.Param
+	classes map[string]int
+}
+
+// ImportClassification brings the catalog in line with the release in one transaction.
+// The items imported before are found by the codes of the system, the others by the names,
+// so the release can be imported over the classes typed in by hand and every next release
+// renames, moves and extends what is already there. Nothing is deleted: the values that left
+// the release are retired, the params and the classes that left it are only reported
+func (do *DbOperator) ImportClassification(rel *classification.Release, unit string) (*ClassificationReport, error) {
+	if unit == "" {
+		unit = DefaultClassificationUnit
+	}
+	report := &ClassificationReport{
+		System:   rel.System,
+		Release:  rel.Release,
+		Created:  make(map[string]int),
+		Updated:  make(map[string]int),
+		Warnings: []string{},
+	}
+	f := func(tx pgx.Tx) error {
+		imp := &classificationImport{
+			do:      do,
+			tx:      tx,
+			rel:     rel,
+			report:  report,
+			unit:    unit,
+			units:   make(map[string]string),
+			params:  make(map[string]*internal.Param),
+			classes: make(map[string]int),
+		}
+		return imp.run()
+	}
+	return report, do.cs.WrapIntoTransaction(context.Background(), f)
+}
+
+func (imp *classificationImport) run() error {
+	eis, err := imp.do.r_EI(imp.tx, imp.unit, nil)
+	if err != nil {
+		return err
+	}
+	if len(eis) == 0 {
+		if _, err := imp.do.cr_EI(imp.tx, &internal.EI{Name: imp.unit, ShortName: imp.unit}); err != nil {
+			return err
+		}
+		imp.report.Created[dumpEIs]++
+	}
+	for _, u := range imp.rel.Units {
+		if err := imp.importUnit(u); err != nil {
+			return fmt.Errorf("unit %s: %w", u.Code, err)
+		}
+	}
+	values := make(map[string]*classification.Value)
+	for _, v := range imp.rel.Values {
+		values[v.Code] = v
+	}
+	// the catalog has a value list and a unit per param, so they are gathered from all the classes
+	featureValues := make(map[string][]*classification.Value)
+	featureUnits := make(map[string]string)
+	seen := make(map[string]bool)
+	for _, c := range imp.rel.AllClasses() {
+		for _, cf := range c.Features {
+			if featureUnits[cf.Code] == "" {
+				featureUnits[cf.Code] = cf.Unit
+			}
+			for _, v := range cf.Values {
+				if key := cf.Code + "/" + v.Code; !seen[key] {
+					seen[key] = true
+					featureValues[cf.Code] = append(featureValues[cf.Code], values[v.Code])
+				}
+			}
+		}
+	}
+	for _, f := range imp.rel.Features {
+		unit := f.Unit
+		if unit == "" {
+			unit = featureUnits[f.Code]
+		}
+		if err := imp.importFeature(f, unit, featureValues[f.Code]); err != nil {
+			return fmt.Errorf("feature %s: %w", f.Code, err)
+		}
+	}
+	tree, err := imp.rel.Tree()
+	if err != nil {
+		return newInvalidErr("%w", err)
+	}
+	for _, c := range tree {
+		if err := imp.importClass(c); err != nil {
+			return fmt.Errorf("class %s: %w", c.Code, err)
+		}
+	}
+	return nil
+}
+
+func (imp *classificationImport) importUnit(u *classification.Unit) error {
+	ei := &internal.EI{Name: u.Name, ShortName: u.ShortName}
+	if ei.ShortName == "" {
+		ei.ShortName = u.Code
+	}
+	id, err := imp.do.r_ClassificationCode(imp.tx, imp.rel.System, codeUnit, u.Code)
+	if err == nil {
+		var name, shortName string
+		err = imp.tx.QueryRow(context.Background(),
+			`SELECT NAME, SHORT_NAME
+				FROM EI
+				WHERE ID_EI = $1`,
+			id).Scan(&name, &shortName)
+		if err == nil && (name != ei.Name || shortName != ei.ShortName) {
+			if _, err := imp.tx.Exec(context.Background(),
+				`UPDATE EI
+					SET NAME = $2, SHORT_NAME = $3
+					WHERE ID_EI = $1`,
+				id, ei.Name, ei.ShortName); err != nil {
+				return err
+			}
+			imp.report.Updated[dumpEIs]++
+		}
+	}
+	if err == pgx.ErrNoRows {
+		existing, err := imp.do.r_EI(imp.tx, ei.Name, nil)
+		if err != nil {
+			return err
+		}
+		if len(existing) != 0 {
+			id = existing[0].Id
+		} else {
+			if id, err = imp.do.cr_EI(imp.tx, ei); err != nil {
+				return err
+			}
+			imp.report.Created[dumpEIs]++
+		}
+	} else if err != nil {
+		return err
+	}
+	imp.units[u.Code] = ei.Name
+	return imp.do.u_ClassificationCode(imp.tx, imp.rel.System, codeUnit, u.Code, id)
+}
+
+// importFeature makes the param of the feature or adopts the param of the same name. A param keeps
+// its value type and ei, as the products may already have values for it, so a different type
+// or unit of the feature is only reported
+func (imp *classificationImport) importFeature(f *classification.Feature, unit string, values []*classification.Value) error {
+	var valType string
+	var err error
+	switch {
+	case f.Type == classification.TypeRange:
+		imp.report.warn("feature %s %s: range features are not imported", f.Code, f.Name)
+		return nil
+	case f.Type == classification.TypeNumeric:
+		valType, err = imp.baseValueType(internal.BaseTypeDecimal)
+	case f.Type == classification.TypeLogical:
+		valType, err = imp.baseValueType(internal.BaseTypeBoolean)
+	case len(values) != 0:
+		valType, err = imp.importValueList(f, values)
+	default:
+		valType, err = imp.baseValueType(internal.BaseTypeString)
+	}
+	if err != nil {
+		return err
+	}
+	ei := imp.unit
+	if unit != "" {
+		ei = imp.units[unit]
+	}
+	param := &internal.Param{Name: f.Name, ValType: valType, EI: &internal.EI{Name: ei}}
+	id, err := imp.do.r_ClassificationCode(imp.tx, imp.rel.System, codeFeature, f.Code)
+	var existing *internal.Param
+	if err == nil {
+		existing, err = imp.do.r_Param(imp.tx, id)
+	}
+	switch {
+	case err == pgx.ErrNoRows:
+		pp, err := imp.do.r_Params(imp.tx, f.Name)
+		if err != nil {
+			return err
+		}
+		if len(pp) == 0 {
+			if id, err = imp.do.cr_Param(imp.tx, param); err != nil {
+				return err
+			}
+			imp.report.Created[dumpParams]++
+			break
+		}
+		// the param of the same name is adopted with the type and the ei it has
+		if pp[0].ValType != valType || pp[0].EI.Name != ei {
+			imp.report.warn("feature %s %s: adopted param keeps value type %s and ei %s instead of %s and %s",
+				f.Code, f.Name, pp[0].ValType, pp[0].EI.Name, valType, ei)
+		}
+		id, param = pp[0].Id, pp[0]
+	case err != nil:
+		return err
+	default:
+		if existing.Name != f.Name {
+			if _, err := imp.tx.Exec(context.Background(),
+				`UPDATE PARAMS
+					SET NAME = $2
+					WHERE ID_PARAM = $1`,
+				id, f.Name); err != nil {
+				return err
+			}
+			imp.report.Updated[dumpParams]++
+		}
+		if existing.ValType != valType || existing.EI.Name != ei {
+			imp.report.warn("feature %s %s: param keeps value type %s and ei %s", f.Code, f.Name, existing.ValType, existing.EI.Name)
+		}
+		param = existing
+		param.Name = f.Name
+	}
+	imp.params[f.Code] = param
+	return imp.do.u_ClassificationCode(imp.tx, imp.rel.System, codeFeature, f.Code, id)
+}
+
+// baseValueType finds the value type named after the base type or creates it
+func (imp *classificationImport) baseValueType(baseType string) (string, error) {
+	_, existing, err := imp.do.r_ValueTypeId(imp.tx, baseType)
+	if err == pgx.ErrNoRows {
+		if err := imp.do.c_ValueType(imp.tx, &internal.ValueType{Name: baseType, BaseType: baseType}); err != nil {
+			return "", err
+		}
+		imp.report.Created[dumpValueTypes]++
+		return baseType, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if existing != baseType {
+		return "", newConflictErr("value type %s already exists with base type %s", baseType, existing)
+	}
+	return baseType, nil
+}
+
+// importValueList makes the enum value type named after the code of the feature. The values
+// are matched by their codes, so they are renamed with the release, the ones it dropped are retired
+func (imp *classificationImport) importValueList(f *classification.Feature, values []*classification.Value) (string, error) {
+	id, baseType, err := imp.do.r_ValueTypeId(imp.tx, f.Code)
+	if err == pgx.ErrNoRows {
+		if err := imp.do.c_ValueType(imp.tx, &internal.ValueType{Name: f.Code, BaseType: internal.BaseTypeEnum}); err != nil {
+			return "", err
+		}
+		if id, baseType, err = imp.do.r_ValueTypeId(imp.tx, f.Code); err != nil {
+			return "", err
+		}
+		imp.report.Created[dumpValueTypes]++
+	} else if err != nil {
+		return "", err
+	}
+	if baseType != internal.BaseTypeEnum {
+		return "", newConflictErr("value type %s already exists with base type %s", f.Code, baseType)
+	}
+	kept := make([]int, 0, len(values))
+	for _, v := range values {
+		code := f.Code + "/" + v.Code
+		idValue, err := imp.do.r_ClassificationCode(imp.tx, imp.rel.System, codeValue, code)
+		if err == nil {
+			var value string
+			var retired bool
+			err = imp.tx.QueryRow(context.Background(),
+				`SELECT VALUE, RETIRED
+					FROM ENUM_VALUES
+					WHERE ID_ENUM_VALUE = $1 AND ID_VALUE_TYPE = $2`,
+				idValue, id).Scan(&value, &retired)
+			if err == nil && value != v.Name {
+				// another value of the type may hold the name, the unique violation would abort the import
+				var taken bool
+				if err := imp.tx.QueryRow(context.Background(),
+					`SELECT EXISTS (
+						SELECT 1
+						FROM ENUM_VALUES
+						WHERE ID_VALUE_TYPE = $1 AND VALUE = $2 AND ID_ENUM_VALUE <> $3)`,
+					id, v.Name, idValue).Scan(&taken); err != nil {
+					return "", err
+				}
+				if taken {
+					imp.report.warn("value %s of feature %s: not renamed from %s, another value has the name", v.Code, f.Code, value)
+				} else {
+					value = v.Name
+				}
+			}
+			if err == nil && (value == v.Name || retired) {
+				if tag, err := imp.tx.Exec(context.Background(),
+					`UPDATE ENUM_VALUES
+						SET VALUE = $2, RETIRED = FALSE
+						WHERE ID_ENUM_VALUE = $1 AND (VALUE <> $2 OR RETIRED)`,
+					idValue, value); err != nil {
+					return "", err
+				} else if tag.RowsAffected() != 0 {
+					imp.report.Updated[classificationValues]++
+				}
+			}
+		}
+		if err == pgx.ErrNoRows {
+			var created bool
+			if idValue, created, err = imp.do.cr_EnumValue(imp.tx, id, v.Name); err != nil {
+				return "", err
+			}
+			if created {
+				imp.report.Created[classificationValues]++
+			}
+		} else if err != nil {
+			return "", err
+		}
+		if err := imp.do.u_ClassificationCode(imp.tx, imp.rel.System, codeValue, code, idValue); err != nil {
+			return "", err
+		}
+		kept = append(kept, idValue)
+	}
+	tag, err := imp.tx.Exec(context.Background(),
+		`UPDATE ENUM_VALUES
+			SET RETIRED = TRUE
+			WHERE ID_VALUE_TYPE = $1 AND NOT RETIRED AND NOT (ID_ENUM_VALUE = ANY($2))`,
+		id, kept)
+	if err != nil {
+		return "", err
+	}
+	imp.report.Updated[classificationValues] += int(tag.RowsAffected())
+	return f.Code, nil
+}
+
+// importClass makes the class under the parent of the release and attaches the params of its features
+func (imp *classificationImport) importClass(c *classification.Class) error {
+	parent := sql.NullInt32{}
+	if code := c.ParentCode(); code != "" {
+		parent = sql.NullInt32{Int32: int32(imp.classes[code]), Valid: true}
+	}
+	var name string
+	var currentParent sql.NullInt32
+	readClass := func(id int) error {
+		return imp.tx.QueryRow(context.Background(),
+			`SELECT NAME, ID_PARENT_CLASS
+				FROM CLASSES
+				WHERE ID_CLASS = $1`,
+			id).Scan(&name, &currentParent)
+	}
+	id, err := imp.do.r_ClassificationCode(imp.tx, imp.rel.System, codeClass, c.Code)
+	if err == nil {
+		err = readClass(id)
+	}
+	if err == pgx.ErrNoRows {
+		id, err = imp.do.r_ClassId(imp.tx, c.Name)
+		if err == nil {
+			err = readClass(id)
+		}
+	}
+	created, changed := err == pgx.ErrNoRows, false
+	switch {
+	case created:
+		if id, err = imp.do.c_Class(imp.tx, &internal.Class{Name: c.Name, Ei: &internal.EI{Name: imp.unit}}, parent); err != nil {
+			return err
+		}
+		name = c.Name
+		imp.report.Created[dumpClasses]++
+	case err != nil:
+		return err
+	default:
+		if name != c.Name {
+			changed, err = imp.renameClass(id, name, c)
+			if err != nil {
+				return err
+			}
+			if changed {
+				name = c.Name
+			}
+		}
+		if currentParent != parent {
+			moved, err := imp.moveClass(id, c, parent)
+			if err != nil {
+				return err
+			}
+			changed = changed || moved
+		}
+	}
+	imp.classes[c.Code] = id
+	if err := imp.do.u_ClassificationCode(imp.tx, imp.rel.System, codeClass, c.Code, id); err != nil {
+		return err
+	}
+	added, err := imp.attachFeatures(id, c)
+	if err != nil {
+		return err
+	}
+	if created || !(changed || added) {
+		return nil
+	}
+	imp.report.Updated[dumpClasses]++
+	return imp.do.u_ClassVersion(imp.tx, id, nil)
+}
+
+// renameClass gives the class the name of the release unless another class holds it,
+// then the class keeps its name
+func (imp *classificationImport) renameClass(id int, name string, c *classification.Class) (bool, error) {
+	other, err := imp.do.r_ClassId(imp.tx, c.Name)
+	switch {
+	case err == nil && other != id:
+		imp.report.warn("class %s %s: not renamed from %s, another class has the name", c.Code, c.Name, name)
+		return false, nil
+	case err != nil && err != pgx.ErrNoRows:
+		return false, err
+	}
+	_, err = imp.tx.Exec(context.Background(),
+		`UPDATE CLASSES
+			SET NAME = $2
+			WHERE ID_CLASS = $1`,
+		id, c.Name)
+	return err == nil, err
+}
+
+// moveClass puts the class under the new parent unless the products of the subtree
+// don't fit the params inherited there, then the class is left where it is
+func (imp *classificationImport) moveClass(id int, c *classification.Class, parent sql.NullInt32) (bool, error) {
+	sp, err := imp.tx.Begin(context.Background())
+	if err != nil {
+		return false, err
+	}
+	err = imp.do.u_ClassParent(sp, id, int(parent.Int32))
+	var conflicts []*productConflicts
+	if err == nil {
+		conflicts, err = imp.do.r_SubtreeConflicts(sp, id)
+	}
+	var iErr *InvalidErr
+	switch {
+	case errors.As(err, &iErr):
+		imp.report.warn("class %s %s: not moved, %v", c.Code, c.Name, iErr)
+	case err != nil:
+		sp.Rollback(context.Background())
+		return false, err
+	case len(conflicts) != 0:
+		imp.report.warn("class %s %s: not moved, the products of %d classes conflict with the new params", c.Code, c.Name, len(conflicts))
+	default:
+		return true, sp.Commit(context.Background())
+	}
+	return false, sp.Rollback(context.Background())
+}
+
+// attachFeatures adds the params of the features the class doesn't have yet. A param that is already
+// attached to a related class is reported, as are the params of the class that left the release
+func (imp *classificationImport) attachFeatures(id int, c *classification.Class) (bool, error) {
+	class, err := imp.do.r_Class(imp.tx, id, true)
+	if err != nil {
+		return false, err
+	}
+	own := make(map[string]bool)
+	for _, p := range class.Params {
+		if p.IdParamOwner == id {
+			own[p.Name] = true
+		}
+	}
+	inRelease := make(map[string]bool)
+	added := false
+	for _, cf := range c.Features {
+		p, ok := imp.params[cf.Code]
+		if !ok {
+			continue
+		}
+		inRelease[p.Name] = true
+		if cf.Unit != "" && imp.units[cf.Unit] != p.EI.Name {
+			imp.report.warn("class %s %s: param %s keeps ei %s", c.Code, c.Name, p.Name, p.EI.Name)
+		}
+		if own[p.Name] {
+			continue
+		}
+		sp, err := imp.tx.Begin(context.Background())
+		if err != nil {
+			return false, err
+		}
+		err = imp.do.c_ClassParams(sp, &internal.Class{Name: class.Name, Params: []*internal.Param{{Name: p.Name}}})
+		var iErr *InvalidErr
+		if errors.As(err, &iErr) {
+			imp.report.warn("class %s %s: param %s is not attached, %v", c.Code, c.Name, p.Name, iErr)
+			if err := sp.Rollback(context.Background()); err != nil {
+				return false, err
+			}
+			continue
+		}
+		if err != nil {
+			sp.Rollback(context.Background())
+			return false, err
+		}
+		if err := sp.Commit(context.Background()); err != nil {
+			return false, err
+		}
+		added = true
+	}
+	for name := range own {
+		if !inRelease[name] {
+			imp.report.warn("class %s %s: param %s is not in the release", c.Code, c.Name, name)
+		}
+	}
+	return added, nil
+}
+
+// cr_EnumValue finds the value of the enum type or adds it, a retired value is brought back
+func (do *DbOperator) cr_EnumValue(tx pgx.Tx, idValueType int, value string) (id int, created bool, err error) {
+	err = tx.QueryRow(context.Background(),
+		`UPDATE ENUM_VALUES
+			SET RETIRED = FALSE
+			WHERE ID_VALUE_TYPE = $1 AND VALUE = $2
+			RETURNING ID_ENUM_VALUE`,
+		idValueType, value).Scan(&id)
+	if err == pgx.ErrNoRows {
+		err = tx.QueryRow(context.Background(),
+			`INSERT INTO ENUM_VALUES(ID_VALUE_TYPE, VALUE)
+				VALUES($1,$2)
+				RETURNING ID_ENUM_VALUE`,
+			idValueType, value).Scan(&id)
+		created = err == nil
+	}
+	return
+}
+
+// CLASSIFICATION_CODES
+
+func (do *DbOperator) r_ClassificationCode(tx pgx.Tx, system, kind, code string) (id int, err error) {
+	err = tx.QueryRow(context.Background(),
+		`SELECT ID
+			FROM CLASSIFICATION_CODES
+			WHERE SYSTEM = $1 AND KIND = $2 AND CODE = $3`,
+		system, kind, code).Scan(&id)
+	return
+}
+
+func (do *DbOperator) u_ClassificationCode(tx pgx.Tx, system, kind, code string, id int) error {
+	_, err := tx.Exec(context.Background(),
+		`INSERT INTO CLASSIFICATION_CODES(SYSTEM, KIND, CODE, ID)
+			VALUES($1,$2,$3,$4)
+			ON CONFLICT (SYSTEM, KIND, CODE) DO UPDATE SET ID = EXCLUDED.ID`,
+		system, kind, code, id)
+	return err
+}
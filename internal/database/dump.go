@@ -0,0 +1,686 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"hseSQL/internal"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// the dump is recognized by the format, the version grows when the document changes
+const (
+	DumpFormat  = "hsesql-catalog"
+	DumpVersion = 1
+)
+
+// kinds of the items in the restore report
+const (
+	dumpEIs        = "eis"
+	dumpValueTypes = "value_types"
+	dumpParams     = "params"
+	dumpClasses    = "classes"
+	dumpProducts   = "products"
+)
+
+// Dump is the whole catalog in one document. The items refer to each other by the names,
+// the ids are only kept to tell the client what they became after the restore
+type Dump struct {
+	Format     string                `json:"format"`
+	Version    int                   `json:"version"`
+	Created    time.Time             `json:"created"`
+	Dimensions []string              `json:"dimensions"`
+	EIs        []*internal.EI        `json:"eis"`
+	ValueTypes []*internal.ValueType `json:"value_types"`
+	// Params is the dictionary of the params, the classes list the params they define with their constraints
+	Params   []*internal.Param `json:"params"`
+	Classes  []*internal.Class `json:"classes"`
+	Products []*DumpProduct    `json:"products"`
+}
+
+// DumpProduct keeps the values of the product by the param names in the EIs of the params
+type DumpProduct struct {
+	Id     int                    `json:"id"`
+	Name   string                 `json:"name"`
+	Class  string                 `json:"class"`
+	Values map[string]interface{} `json:"values"`
+}
+
+// RestoreReport maps the ids of the dump to the ids in the database by the kind of the items.
+// The items that were already in the database by the name are reused, the others are created
+type RestoreReport struct {
+	Ids     map[string]map[int]int `json:"ids"`
+	Created map[string]int         `json:"created"`
+	Reused  map[string]int         `json:"reused"`
+}
+
+func (r *RestoreReport) add(kind string, dumpId, id int, created bool) {
+	if r.Ids[kind] == nil {
+		r.Ids[kind] = make(map[int]int)
+	}
+	if dumpId != 0 {
+		r.Ids[kind][dumpId] = id
+	}
+	if created {
+		r.Created[kind]++
+	} else {
+		r.Reused[kind]++
+	}
+}
+
+// DecodeDump reads the dump keeping the numbers as they are written, so big integers survive
+func DecodeDump(r io.Reader) (*Dump, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	d := &Dump{}
+	if err := decoder.Decode(d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// DumpCatalog reads the whole catalog in one transaction, so the dump is consistent
+func (do *DbOperator) DumpCatalog() (*Dump, error) {
+	d := &Dump{
+		Format:     DumpFormat,
+		Version:    DumpVersion,
+		Created:    time.Now().UTC(),
+		Dimensions: []string{},
+		Products:   []*DumpProduct{},
+	}
+	f := func(tx pgx.Tx) error {
+		dims, err := do.r_Dimensions(tx)
+		if err != nil {
+			return err
+		}
+		for _, dim := range dims {
+			d.Dimensions = append(d.Dimensions, dim.Name)
+		}
+		if d.EIs, err = do.r_EI(tx, "", nil); err != nil {
+			return err
+		}
+		if d.ValueTypes, err = do.r_ValueType(tx, nil); err != nil {
+			return err
+		}
+		if d.Params, err = do.r_Params(tx, ""); err != nil {
+			return err
+		}
+		if d.Classes, err = do.r_DumpClasses(tx); err != nil {
+			return err
+		}
+		d.Products, err = do.r_DumpProducts(tx)
+		return err
+	}
+	return d, do.cs.WrapIntoTransaction(context.Background(), f)
+}
+
+// r_DumpClasses reads the class tree, every class has the params it defines and its overrides
+func (do *DbOperator) r_DumpClasses(tx pgx.Tx) ([]*internal.Class, error) {
+	rows, err := tx.Query(context.Background(),
+		`SELECT ID_CLASS, ID_PARENT_CLASS
+			FROM CLASSES
+			ORDER BY ID_CLASS`)
+	if err != nil {
+		return nil, err
+	}
+	parents := make(map[int]sql.NullInt32)
+	var ids []int
+	for rows.Next() {
+		var id int
+		var idParent sql.NullInt32
+		if err := rows.Scan(&id, &idParent); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+		parents[id] = idParent
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	overrides, err := do.r_ClassOverrides(tx)
+	if err != nil {
+		return nil, err
+	}
+	classes := make(map[int]*internal.Class)
+	for _, id := range ids {
+		c, err := do.r_Class(tx, id, true)
+		if err != nil {
+			return nil, err
+		}
+		var own []*internal.Param
+		for _, p := range c.Params {
+			if p.IdParamOwner == id {
+				own = append(own, p)
+			}
+		}
+		c.Params = own
+		c.Children = []*internal.Class{}
+		c.Overrides = overrides[id]
+		c.Version = 0
+		classes[id] = c
+	}
+	// the parents are linked after all the classes are read, as a moved class may have a smaller id
+	roots := []*internal.Class{}
+	for _, id := range ids {
+		if parent := parents[id]; parent.Valid {
+			classes[int(parent.Int32)].Children = append(classes[int(parent.Int32)].Children, classes[id])
+			continue
+		}
+		roots = append(roots, classes[id])
+	}
+	return roots, nil
+}
+
+// r_ClassOverrides reads the overrides as they are stored by the class ids
+func (do *DbOperator) r_ClassOverrides(tx pgx.Tx) (map[int][]*internal.ParamOverride, error) {
+	rows, err := tx.Query(context.Background(),
+		`SELECT CPO.ID_CLASS, P.NAME, CPO.MIN_VALUE::FLOAT8, CPO.MAX_VALUE::FLOAT8, CPO.STEP::FLOAT8,
+				CPO.PRECISION, CPO.MAX_LENGTH, CPO.REQUIRED, CPO.DEFAULT_VALUE::TEXT, EI.NAME, EI.SHORT_NAME
+			FROM CLASS_PARAM_OVERRIDES CPO JOIN CLASS_PARAMS CP ON CP.ID_CLASS_PARAM = CPO.ID_CLASS_PARAM
+							JOIN PARAMS P ON P.ID_PARAM = CP.ID_PARAM
+							LEFT JOIN EI ON EI.ID_EI = CPO.ID_DISPLAY_EI
+			ORDER BY CPO.ID_CLASS, P.NAME`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := make(map[int][]*internal.ParamOverride)
+	for rows.Next() {
+		var idClass int
+		var defaultValue, eiName, eiShortName sql.NullString
+		o := &internal.ParamOverride{Constraints: &internal.ParamConstraints{}}
+		if err := rows.Scan(&idClass, &o.Name, &o.Constraints.Min, &o.Constraints.Max, &o.Constraints.Step,
+			&o.Constraints.Precision, &o.Constraints.MaxLength, &o.Required, &defaultValue,
+			&eiName, &eiShortName); err != nil {
+			return nil, err
+		}
+		if *o.Constraints == (internal.ParamConstraints{}) {
+			o.Constraints = nil
+		}
+		if o.Default, err = decodeDefault(defaultValue); err != nil {
+			return nil, err
+		}
+		if eiName.Valid {
+			o.DisplayEI = &internal.EI{
+				Name:      eiName.String,
+				ShortName: eiShortName.String,
+			}
+		}
+		result[idClass] = append(result[idClass], o)
+	}
+	return result, rows.Err()
+}
+
+func (do *DbOperator) r_DumpProducts(tx pgx.Tx) ([]*DumpProduct, error) {
+	rows, err := tx.Query(context.Background(),
+		`SELECT ID_PRODUCT
+			FROM PRODUCTS
+			ORDER BY ID_PRODUCT`)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	pp, err := do.r_Products(tx, ids)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*DumpProduct, 0, len(pp))
+	for _, p := range pp {
+		dp := &DumpProduct{
+			Id:     p.Id,
+			Name:   p.Name,
+			Class:  p.ParentClass.Name,
+			Values: make(map[string]interface{}),
+		}
+		for _, pnv := range p.Params {
+			dp.Values[pnv.Param.Name] = pnv.Value
+		}
+		result = append(result, dp)
+	}
+	return result, nil
+}
+
+// RestoreCatalog writes the dump into the database in one transaction. The items are matched
+// by the names, so the dump can be restored into an empty database as well as into the one
+// it was made of: existing units, value types, params and classes are kept, missing class params,
+// overrides and enum values are added and existing products get the values of the dump.
+// Restoring the same dump again changes nothing
+func (do *DbOperator) RestoreCatalog(d *Dump) (*RestoreReport, error) {
+	if d.Format != DumpFormat {
+		return nil, newInvalidErr("not a dump of the catalog")
+	}
+	if d.Version < 1 || d.Version > DumpVersion {
+		return nil, newInvalidErr("dump version %d is not supported, the latest one is %d", d.Version, DumpVersion)
+	}
+	report := &RestoreReport{
+		Ids:     make(map[string]map[int]int),
+		Created: make(map[string]int),
+		Reused:  make(map[string]int),
+	}
+	f := func(tx pgx.Tx) error {
+		for _, name := range d.Dimensions {
+			if _, err := do.cr_Dimension(tx, name); err != nil {
+				return fmt.Errorf("dimension %s: %w", name, err)
+			}
+		}
+		for _, ei := range d.EIs {
+			if err := do.restoreEI(tx, ei, report); err != nil {
+				return fmt.Errorf("ei %s: %w", ei.Name, err)
+			}
+		}
+		valueTypes := make(map[string]int)
+		for _, vt := range d.ValueTypes {
+			id, err := do.restoreValueType(tx, vt, report)
+			if err != nil {
+				return fmt.Errorf("value type %s: %w", vt.Name, err)
+			}
+			valueTypes[vt.Name] = id
+		}
+		for _, p := range d.Params {
+			if err := do.restoreParam(tx, p, report); err != nil {
+				return fmt.Errorf("param %s: %w", p.Name, err)
+			}
+		}
+		for _, c := range d.Classes {
+			if err := do.restoreClass(tx, c, sql.NullInt32{}, report); err != nil {
+				return err
+			}
+		}
+		for _, p := range d.Products {
+			if err := do.restoreProduct(tx, p, report); err != nil {
+				return fmt.Errorf("product %s: %w", p.Name, err)
+			}
+		}
+		// values are retired after the products are written, as the products may still use them
+		for _, vt := range d.ValueTypes {
+			for _, v := range vt.Values {
+				if !v.Retired {
+					continue
+				}
+				if err := do.d_EnumValue(tx, valueTypes[vt.Name], v.Value); err != nil {
+					return fmt.Errorf("value type %s: %w", vt.Name, err)
+				}
+			}
+		}
+		return nil
+	}
+	return report, do.cs.WrapIntoTransaction(context.Background(), f)
+}
+
+// restoreEI creates the EI or checks the existing one converts the same way
+func (do *DbOperator) restoreEI(tx pgx.Tx, ei *internal.EI, report *RestoreReport) error {
+	existing, err := do.r_EI(tx, ei.Name, nil)
+	if err != nil {
+		return err
+	}
+	if len(existing) != 0 {
+		e := existing[0]
+		factor := ei.Factor
+		if factor == 0 {
+			factor = 1
+		}
+		if e.Dimension != ei.Dimension || e.Factor != factor || e.Offset != ei.Offset {
+			return newConflictErr("ei %s already exists with dimension %q, factor %v and offset %v",
+				ei.Name, e.Dimension, e.Factor, e.Offset)
+		}
+		report.add(dumpEIs, ei.Id, e.Id, false)
+		return nil
+	}
+	id, err := do.cr_EI(tx, ei)
+	if err != nil {
+		return err
+	}
+	report.add(dumpEIs, ei.Id, id, true)
+	return nil
+}
+
+// restoreValueType creates the value type or checks the existing one has the same base type,
+// the enum values are added or brought back from the retired ones
+func (do *DbOperator) restoreValueType(tx pgx.Tx, vt *internal.ValueType, report *RestoreReport) (int, error) {
+	baseType := vt.BaseType
+	if baseType == "" {
+		baseType = internal.BaseTypeString
+	}
+	id, existingBaseType, err := do.r_ValueTypeId(tx, vt.Name)
+	created := err == pgx.ErrNoRows
+	switch {
+	case created:
+		if err := do.c_ValueType(tx, &internal.ValueType{Name: vt.Name, BaseType: baseType}); err != nil {
+			return 0, err
+		}
+		if id, _, err = do.r_ValueTypeId(tx, vt.Name); err != nil {
+			return 0, err
+		}
+	case err != nil:
+		return 0, err
+	case existingBaseType != baseType:
+		return 0, newConflictErr("value type already exists with base type %s", existingBaseType)
+	}
+	for _, v := range vt.Values {
+		if _, err := tx.Exec(context.Background(),
+			`INSERT INTO ENUM_VALUES(ID_VALUE_TYPE, VALUE)
+				VALUES($1,$2)
+				ON CONFLICT (ID_VALUE_TYPE, VALUE) DO UPDATE SET RETIRED = FALSE WHERE ENUM_VALUES.RETIRED`,
+			id, v.Value); err != nil {
+			return 0, err
+		}
+	}
+	report.add(dumpValueTypes, vt.Id, id, created)
+	return id, nil
+}
+
+// restoreParam creates the param or checks the existing one has the same value type and EI
+func (do *DbOperator) restoreParam(tx pgx.Tx, p *internal.Param, report *RestoreReport) error {
+	existing, err := do.r_Params(tx, p.Name)
+	if err != nil {
+		return err
+	}
+	if len(existing) != 0 {
+		e := existing[0]
+		if (p.ValType != "" && p.ValType != e.ValType) || (p.EI != nil && p.EI.Name != "" && p.EI.Name != e.EI.Name) {
+			return newConflictErr("param %s already exists with value type %s and ei %s", p.Name, e.ValType, e.EI.Name)
+		}
+	}
+	param := &internal.Param{Name: p.Name, ValType: p.ValType, EI: p.EI}
+	id, err := do.cr_Param(tx, param)
+	if err != nil {
+		return err
+	}
+	report.add(dumpParams, p.Id, id, len(existing) == 0)
+	return nil
+}
+
+// restoreClass creates the class under the parent or adds the missing params and overrides
+// to the existing one, which has to be under the same parent. The children follow the class
+func (do *DbOperator) restoreClass(tx pgx.Tx, c *internal.Class, parent sql.NullInt32, report *RestoreReport) error {
+	params := make([]*internal.Param, 0, len(c.Params))
+	for _, p := range c.Params {
+		params = append(params, &internal.Param{
+			Name:        p.Name,
+			ValType:     p.ValType,
+			EI:          p.EI,
+			Constraints: p.Constraints,
+			Required:    p.Required,
+			Default:     p.Default,
+		})
+	}
+	id, err := do.r_ClassId(tx, c.Name)
+	created := err == pgx.ErrNoRows
+	switch {
+	case created:
+		if id, err = do.c_Class(tx, &internal.Class{
+			Name:      c.Name,
+			Ei:        c.Ei,
+			Params:    params,
+			Overrides: c.Overrides,
+		}, parent); err != nil {
+			return fmt.Errorf("class %s: %w", c.Name, err)
+		}
+	case err != nil:
+		return err
+	default:
+		if err := do.restoreClassParams(tx, id, c.Name, parent, params, c.Overrides); err != nil {
+			return fmt.Errorf("class %s: %w", c.Name, err)
+		}
+	}
+	report.add(dumpClasses, c.Id, id, created)
+	for _, child := range c.Children {
+		if err := do.restoreClass(tx, child, sql.NullInt32{Int32: int32(id), Valid: true}, report); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (do *DbOperator) restoreClassParams(tx pgx.Tx, id int, name string, parent sql.NullInt32,
+	params []*internal.Param, overrides []*internal.ParamOverride) error {
+	existingParent, err := do.r_ParentClass(tx, name)
+	if err != nil {
+		return err
+	}
+	if existingParent.Valid != parent.Valid || existingParent.Int32 != parent.Int32 {
+		return newConflictErr("class already exists under another parent")
+	}
+	class, err := do.r_Class(tx, id, true)
+	if err != nil {
+		return err
+	}
+	has := make(map[string]*internal.Param)
+	for _, p := range class.Params {
+		has[p.Name] = p
+	}
+	var missing []*internal.Param
+	var diffs []string
+	for _, p := range params {
+		e, ok := has[p.Name]
+		switch {
+		case !ok:
+			missing = append(missing, p)
+		case e.IdParamOwner != id:
+			diffs = append(diffs, fmt.Sprintf("param %s is inherited, the dump defines it in the class", p.Name))
+		default:
+			diffs = append(diffs, paramDiffs(e, p)...)
+		}
+	}
+	if err := do.c_ClassParams(tx, &internal.Class{Name: name, Params: missing}); err != nil {
+		return err
+	}
+	stored, err := do.r_ClassOverrides(tx)
+	if err != nil {
+		return err
+	}
+	overridden := make(map[string]*internal.ParamOverride)
+	for _, o := range stored[id] {
+		overridden[o.Name] = o
+	}
+	var missingOverrides []*internal.ParamOverride
+	for _, o := range overrides {
+		if e, ok := overridden[o.Name]; ok {
+			diffs = append(diffs, overrideDiffs(e, o)...)
+			continue
+		}
+		missingOverrides = append(missingOverrides, o)
+	}
+	// the rules of the existing class are kept only when they are the ones of the dump
+	if len(diffs) != 0 {
+		return newConflictErr("%s", strings.Join(diffs, "; "))
+	}
+	if len(missing) == 0 && len(missingOverrides) == 0 {
+		return nil
+	}
+	if err := do.c_ParamOverrides(tx, id, missingOverrides); err != nil {
+		return err
+	}
+	return do.u_ClassVersion(tx, id, nil)
+}
+
+// paramDiffs lists how the param of the existing class differs from the one of the dump
+func paramDiffs(e, p *internal.Param) []string {
+	var diffs []string
+	if !sameConstraints(e.Constraints, p.Constraints) {
+		diffs = append(diffs, fmt.Sprintf("param %s already has other constraints", p.Name))
+	}
+	if e.Required != p.Required {
+		diffs = append(diffs, fmt.Sprintf("param %s already has required %v", p.Name, e.Required))
+	}
+	if !sameDefault(e.Default, p.Default) {
+		diffs = append(diffs, fmt.Sprintf("param %s already has another default", p.Name))
+	}
+	return diffs
+}
+
+// overrideDiffs lists how the stored override differs from the one of the dump
+func overrideDiffs(e, o *internal.ParamOverride) []string {
+	var diffs []string
+	if !sameConstraints(e.Constraints, o.Constraints) {
+		diffs = append(diffs, fmt.Sprintf("override of param %s already has other constraints", o.Name))
+	}
+	if (e.Required == nil) != (o.Required == nil) || (e.Required != nil && *e.Required != *o.Required) {
+		diffs = append(diffs, fmt.Sprintf("override of param %s already has another required flag", o.Name))
+	}
+	if !sameDefault(e.Default, o.Default) {
+		diffs = append(diffs, fmt.Sprintf("override of param %s already has another default", o.Name))
+	}
+	if eiName(e.DisplayEI) != eiName(o.DisplayEI) {
+		diffs = append(diffs, fmt.Sprintf("override of param %s already has display ei %q", o.Name, eiName(e.DisplayEI)))
+	}
+	return diffs
+}
+
+func eiName(ei *internal.EI) string {
+	if ei == nil {
+		return ""
+	}
+	return ei.Name
+}
+
+// sameConstraints compares the constraints, no constraints are the same as the empty ones
+func sameConstraints(a, b *internal.ParamConstraints) bool {
+	if a == nil {
+		a = &internal.ParamConstraints{}
+	}
+	if b == nil {
+		b = &internal.ParamConstraints{}
+	}
+	sameFloat := func(x, y *float64) bool {
+		return (x == nil) == (y == nil) && (x == nil || ratOf(*x).Cmp(ratOf(*y)) == 0)
+	}
+	sameInt := func(x, y *int) bool {
+		return (x == nil) == (y == nil) && (x == nil || *x == *y)
+	}
+	return sameFloat(a.Min, b.Min) && sameFloat(a.Max, b.Max) && sameFloat(a.Step, b.Step) &&
+		sameInt(a.Precision, b.Precision) && sameInt(a.MaxLength, b.MaxLength)
+}
+
+// sameDefault compares the defaults as json, so the numbers of the dump match the stored ones
+func sameDefault(a, b interface{}) bool {
+	normalize := func(v interface{}) interface{} {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return v
+		}
+		var res interface{}
+		if err := json.Unmarshal(data, &res); err != nil {
+			return v
+		}
+		return res
+	}
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
+
+// restoreProduct creates the product or replaces the class and the values of the existing one
+func (do *DbOperator) restoreProduct(tx pgx.Tx, dp *DumpProduct, report *RestoreReport) error {
+	p := &internal.Product{
+		Name:        dp.Name,
+		ParentClass: &internal.Class{Name: dp.Class},
+	}
+	names := make([]string, 0, len(dp.Values))
+	for name := range dp.Values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p.Params = append(p.Params, &internal.ParamAndValues{
+			Param: &internal.Param{Name: name},
+			Value: dp.Values[name],
+		})
+	}
+	id, err := do.r_ProductId(tx, dp.Name)
+	created := err == pgx.ErrNoRows
+	switch {
+	case created:
+		id, err = do.c_Product(tx, p)
+	case err != nil:
+		return err
+	default:
+		var same bool
+		if same, err = do.sameProduct(tx, id, dp); err != nil || same {
+			break
+		}
+		if err := do.u_ProductVersion(tx, id, nil); err != nil {
+			return err
+		}
+		p.Id = id
+		err = do.u_Product(tx, p)
+	}
+	// the product was found, so only its class can be missing
+	if errors.Is(err, pgx.ErrNoRows) {
+		return newInvalidErr("couldn't find class %s", dp.Class)
+	}
+	if err != nil {
+		return err
+	}
+	report.add(dumpProducts, dp.Id, id, created)
+	return nil
+}
+
+// sameProduct tells if the values of the dump would leave the stored product as it is,
+// the params the dump has no values for are expected to have their defaults
+func (do *DbOperator) sameProduct(tx pgx.Tx, id int, dp *DumpProduct) (bool, error) {
+	current, err := do.r_Product(tx, id)
+	if err != nil {
+		return false, err
+	}
+	if current.ParentClass.Name != dp.Class {
+		return false, nil
+	}
+	class, err := do.r_Class(tx, current.ParentClass.Id, true)
+	if err != nil {
+		return false, err
+	}
+	stored := make(map[string]interface{})
+	for _, pnv := range current.Params {
+		stored[pnv.Param.Name] = pnv.Value
+	}
+	params := make(map[string]bool)
+	for _, cp := range class.Params {
+		params[cp.Name] = true
+		raw, ok := dp.Values[cp.Name]
+		if !ok {
+			raw = cp.Default
+		}
+		value, has := stored[cp.Name]
+		if raw == nil {
+			if has {
+				return false, nil
+			}
+			continue
+		}
+		tv, err := parseValue(cp.BaseType, raw)
+		if err != nil || !has || tv.value() != value {
+			return false, nil
+		}
+	}
+	for name := range dp.Values {
+		if !params[name] {
+			return false, nil
+		}
+	}
+	for name := range stored {
+		if !params[name] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,40 @@
+package database
+
+import (
+	"encoding/json"
+	"hseSQL/internal"
+	"testing"
+)
+
+func TestParamDiffs(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	stored := &internal.Param{
+		Name:        "weight",
+		Constraints: &internal.ParamConstraints{Min: f(0.1)},
+		Default:     1.5,
+	}
+	tests := []struct {
+		name  string
+		param *internal.Param
+		diffs int
+	}{
+		{"same", &internal.Param{Name: "weight", Constraints: &internal.ParamConstraints{Min: f(0.1)}, Default: json.Number("1.50")}, 0},
+		{"other min", &internal.Param{Name: "weight", Constraints: &internal.ParamConstraints{Min: f(0.2)}, Default: 1.5}, 1},
+		{"no constraints", &internal.Param{Name: "weight", Default: 1.5}, 1},
+		{"required", &internal.Param{Name: "weight", Constraints: &internal.ParamConstraints{Min: f(0.1)}, Required: true, Default: 1.5}, 1},
+		{"no default", &internal.Param{Name: "weight", Constraints: &internal.ParamConstraints{Min: f(0.1)}}, 1},
+	}
+	for _, tt := range tests {
+		if diffs := paramDiffs(stored, tt.param); len(diffs) != tt.diffs {
+			t.Errorf("%s: got %v", tt.name, diffs)
+		}
+	}
+	empty := &internal.ParamOverride{Name: "weight", Constraints: &internal.ParamConstraints{}}
+	if diffs := overrideDiffs(&internal.ParamOverride{Name: "weight"}, empty); len(diffs) != 0 {
+		t.Errorf("empty constraints differ from none: %v", diffs)
+	}
+	display := &internal.ParamOverride{Name: "weight", DisplayEI: &internal.EI{Name: "gram"}}
+	if diffs := overrideDiffs(&internal.ParamOverride{Name: "weight"}, display); len(diffs) != 1 {
+		t.Errorf("display ei: got %v", diffs)
+	}
+}
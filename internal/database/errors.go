@@ -0,0 +1,33 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+)
+
+// InvalidErr is returned when an operation can't be done with the given data or the current
+// state of the catalog, so it is the client who has to fix it. Conflict tells that the request
+// is fine by itself but clashes with the data that is already stored
+type InvalidErr struct {
+	Conflict bool
+	err      error
+}
+
+func newInvalidErr(format string, args ...interface{}) error {
+	return &InvalidErr{err: fmt.Errorf(format, args...)}
+}
+
+func newConflictErr(format string, args ...interface{}) error {
+	return &InvalidErr{Conflict: true, err: fmt.Errorf(format, args...)}
+}
+
+func (e *InvalidErr) Error() string {
+	return e.err.Error()
+}
+
+func (e *InvalidErr) Unwrap() error {
+	return e.err
+}
+
+// ErrVersionMismatch is returned when the row was changed after the client read the version it expects
+var ErrVersionMismatch = errors.New("the version doesn't match, the row was changed")
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+)
+
+// ClassColumn is the column of the class names in the export of a subtree
+const ClassColumn = "class"
+
+// ProductTable is the flat form of the products: a row per product and a column per param,
+// the header gives the unit of every param column. A cell is nil when the product has no value
+type ProductTable struct {
+	Class  string
+	Header []string
+	Rows   [][]interface{}
+}
+
+// ExportClassProducts flattens the products of the class or of its whole subtree into a table.
+// The values are kept in the EIs of the params, so the table can be imported back
+func (do *DbOperator) ExportClassProducts(id int, subtree bool) (*ProductTable, error) {
+	table := &ProductTable{}
+	f := func(tx pgx.Tx) error {
+		class, err := do.r_Class(tx, id, false)
+		if err != nil {
+			return err
+		}
+		table.Class = class.Name
+		ids := []int{id}
+		if subtree {
+			if ids, err = do.r_SubtreeIds(tx, id); err != nil {
+				return err
+			}
+		}
+		table.Header = []string{NameColumn}
+		if subtree {
+			table.Header = append(table.Header, ClassColumn)
+		}
+		// the same param has the same column in every class of the subtree
+		columns := make(map[string]int)
+		for _, idClass := range ids {
+			c, err := do.r_Class(tx, idClass, true)
+			if err != nil {
+				return err
+			}
+			for _, cp := range c.Params {
+				if _, ok := columns[cp.Name]; ok {
+					continue
+				}
+				columns[cp.Name] = len(table.Header)
+				unit := cp.EI.ShortName
+				if unit == "" {
+					unit = cp.EI.Name
+				}
+				table.Header = append(table.Header, ParamHeader(cp.Name, unit))
+			}
+			products, err := do.r_ClassProducts(tx, idClass, nil)
+			if err != nil {
+				return err
+			}
+			for _, p := range products {
+				row := []interface{}{p.Name}
+				if subtree {
+					row = append(row, c.Name)
+				}
+				for _, pnv := range p.Params {
+					idx, ok := columns[pnv.Param.Name]
+					if !ok {
+						continue
+					}
+					for len(row) <= idx {
+						row = append(row, nil)
+					}
+					row[idx] = pnv.Value
+				}
+				table.Rows = append(table.Rows, row)
+			}
+		}
+		// the rows of the first classes don't know about the columns of the next ones
+		for i, row := range table.Rows {
+			for len(row) < len(table.Header) {
+				row = append(row, nil)
+			}
+			table.Rows[i] = row
+		}
+		return nil
+	}
+	return table, do.cs.WrapIntoTransaction(context.Background(), f)
+}
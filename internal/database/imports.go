@@ -0,0 +1,203 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"hseSQL/internal"
+	"regexp"
+	"strings"
+)
+
+// NameColumn is the column of the product names in the imported and exported tables
+const NameColumn = "name"
+
+var errImportRejected = errors.New("some rows are rejected")
+
+// unitHeader matches a column of a param with the unit its values are given in, like "weight [kg]"
+var unitHeader = regexp.MustCompile(`^(.*\S)\s*\[(.+)\]$`)
+
+// ParamHeader is the header of the param column, the values are written in the unit of the header
+func ParamHeader(name, unit string) string {
+	if unit == "" {
+		return name
+	}
+	return fmt.Sprintf("%s [%s]", name, unit)
+}
+
+// ImportRow tells what happened to a row of the imported table, Row is the line number
+// in the file counting the header as the first one
+type ImportRow struct {
+	Row    int           `json:"row"`
+	Name   string        `json:"name"`
+	Id     int           `json:"id,omitempty"`
+	Errors []*FieldError `json:"errors,omitempty"`
+}
+
+// ImportReport lists every row of the imported table. Imported tells that the valid rows were saved,
+// unless it is partial the import saves them only when every row is valid
+type ImportReport struct {
+	Imported bool         `json:"imported"`
+	Created  int          `json:"created"`
+	Failed   int          `json:"failed"`
+	Rows     []*ImportRow `json:"rows"`
+}
+
+type importColumn struct {
+	param *internal.Param
+	unit  string
+}
+
+// ImportClassProducts creates a product from every row of the table, the columns are the name
+// and the effective params of the class matched by the header. Empty cells are left without a value
+func (do *DbOperator) ImportClassProducts(id int, header []string, rows [][]string, partial bool) (*ImportReport, error) {
+	report := &ImportReport{Rows: []*ImportRow{}}
+	f := func(tx pgx.Tx) error {
+		class, err := do.r_Class(tx, id, true)
+		if err != nil {
+			return err
+		}
+		if _, err := do.r_TerminalClass(tx, class.Name); err != nil {
+			return err
+		}
+		nameIdx, columns, err := importColumns(header, class)
+		if err != nil {
+			return err
+		}
+		for i, record := range rows {
+			row, err := do.importRow(tx, class, nameIdx, columns, record)
+			if err != nil {
+				return err
+			}
+			row.Row = i + 2
+			if len(row.Errors) != 0 {
+				report.Failed++
+			} else if row.Id != 0 {
+				report.Created++
+			}
+			report.Rows = append(report.Rows, row)
+		}
+		if report.Failed != 0 && !partial {
+			return errImportRejected
+		}
+		report.Imported = true
+		return nil
+	}
+	err := do.cs.WrapIntoTransaction(context.Background(), f)
+	if errors.Is(err, errImportRejected) {
+		return report, nil
+	}
+	return report, err
+}
+
+// importColumns matches the header with the params of the class, a column of the table
+// without a param is nil
+func importColumns(header []string, class *internal.Class) (int, []*importColumn, error) {
+	params := make(map[string]*internal.Param)
+	for _, cp := range class.Params {
+		params[cp.Name] = cp
+	}
+	nameIdx := -1
+	columns := make([]*importColumn, len(header))
+	seen := make(map[string]bool)
+	for i, h := range header {
+		h = strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))
+		if strings.EqualFold(h, NameColumn) && params[h] == nil {
+			if nameIdx != -1 {
+				return 0, nil, newInvalidErr("column %s is given twice", NameColumn)
+			}
+			nameIdx = i
+			continue
+		}
+		column := &importColumn{param: params[h]}
+		if column.param == nil {
+			if m := unitHeader.FindStringSubmatch(h); m != nil {
+				column = &importColumn{param: params[m[1]], unit: strings.TrimSpace(m[2])}
+			}
+		}
+		if column.param == nil {
+			return 0, nil, newInvalidErr("column %s doesn't match any param of class %s", h, class.Name)
+		}
+		if seen[column.param.Name] {
+			return 0, nil, newInvalidErr("param %s is given twice", column.param.Name)
+		}
+		seen[column.param.Name] = true
+		columns[i] = column
+	}
+	if nameIdx == -1 {
+		return 0, nil, newInvalidErr("column %s is missing", NameColumn)
+	}
+	return nameIdx, columns, nil
+}
+
+// importRow creates the product of the row under a savepoint, so a rejected row
+// leaves the transaction usable for the next ones. Only the errors nobody expected are returned
+func (do *DbOperator) importRow(tx pgx.Tx, class *internal.Class, nameIdx int, columns []*importColumn, record []string) (*ImportRow, error) {
+	row := &ImportRow{}
+	if nameIdx < len(record) {
+		row.Name = strings.TrimSpace(record[nameIdx])
+	}
+	rowErr := func(message string) (*ImportRow, error) {
+		row.Errors = append(row.Errors, &FieldError{Product: row.Name, Message: message})
+		return row, nil
+	}
+	if len(record) > len(columns) {
+		return rowErr(fmt.Sprintf("row has %d cells, the header has %d", len(record), len(columns)))
+	}
+	if row.Name == "" {
+		return rowErr("name is missing")
+	}
+	p := &internal.Product{
+		Name:        row.Name,
+		ParentClass: &internal.Class{Id: class.Id, Name: class.Name},
+	}
+	for i, cell := range record {
+		if columns[i] == nil || strings.TrimSpace(cell) == "" {
+			continue
+		}
+		p.Params = append(p.Params, &internal.ParamAndValues{
+			Param: &internal.Param{Name: columns[i].param.Name},
+			Value: cell,
+			Unit:  columns[i].unit,
+		})
+	}
+	sp, err := tx.Begin(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	id, err := do.c_ClassProduct(sp, p, class)
+	if err != nil {
+		if err := sp.Rollback(context.Background()); err != nil {
+			return nil, err
+		}
+		if row.Errors = rowErrors(p.Name, err); row.Errors == nil {
+			return nil, err
+		}
+		return row, nil
+	}
+	if err := sp.Commit(context.Background()); err != nil {
+		return nil, err
+	}
+	row.Id = id
+	return row, nil
+}
+
+// rowErrors explains why the product of a row was rejected, nil means the error is not about the row
+func rowErrors(name string, err error) []*FieldError {
+	var vErr *ValidationError
+	var iErr *InvalidErr
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.As(err, &vErr):
+		return vErr.Fields
+	case errors.As(err, &iErr):
+		return []*FieldError{{Product: name, Message: iErr.Error()}}
+	case errors.As(err, &pgErr) && pgErr.Code == "23505":
+		return []*FieldError{{Product: name, Message: "product already exists"}}
+	case errors.As(err, &pgErr) && (strings.HasPrefix(pgErr.Code, "22") || strings.HasPrefix(pgErr.Code, "23")):
+		return []*FieldError{{Product: name, Message: pgErr.Message}}
+	}
+	return nil
+}
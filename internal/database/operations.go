@@ -4,8 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/jackc/pgx/v4"
 	"hseSQL/internal"
+	"strings"
 )
 
 type DbOperator struct {
@@ -20,14 +22,32 @@ func NewDbOperator(cs *ConnectionService) *DbOperator {
 
 func (do *DbOperator) CreateTables() error {
 	tables := []string{
+		`CREATE EXTENSION IF NOT EXISTS PG_TRGM`,
+
+		`CREATE TABLE IF NOT EXISTS DIMENSIONS (
+		ID_DIMENSION SERIAL PRIMARY KEY,
+		NAME VARCHAR(100) UNIQUE CHECK (LENGTH(NAME) > 0))`,
+
 		`CREATE TABLE IF NOT EXISTS EI (
 		ID_EI SERIAL PRIMARY KEY,
 		NAME VARCHAR(250) UNIQUE CHECK (LENGTH(NAME) > 0),
-		SHORT_NAME VARCHAR(20) CHECK (LENGTH(NAME) > 0))`,
+		SHORT_NAME VARCHAR(20) CHECK (LENGTH(NAME) > 0),
+		ID_DIMENSION INTEGER REFERENCES DIMENSIONS(ID_DIMENSION) ON DELETE SET NULL,
+		FACTOR NUMERIC NOT NULL DEFAULT 1 CHECK (FACTOR <> 0),
+		OFFSET_VALUE NUMERIC NOT NULL DEFAULT 0)`,
 
 		`CREATE TABLE IF NOT EXISTS VALUE_TYPES (
 		ID_VALUE_TYPE SERIAL PRIMARY KEY,
-		NAME VARCHAR(20) UNIQUE CHECK (LENGTH(NAME) > 0))`,
+		NAME VARCHAR(20) UNIQUE CHECK (LENGTH(NAME) > 0),
+		BASE_TYPE VARCHAR(20) NOT NULL DEFAULT 'string' 
+			CHECK (BASE_TYPE IN ('integer', 'decimal', 'boolean', 'date', 'string', 'enum')))`,
+
+		`CREATE TABLE IF NOT EXISTS ENUM_VALUES (
+		ID_ENUM_VALUE SERIAL PRIMARY KEY,
+		ID_VALUE_TYPE INTEGER REFERENCES VALUE_TYPES(ID_VALUE_TYPE) ON DELETE CASCADE,
+		VALUE VARCHAR(300) CHECK (LENGTH(VALUE) > 0),
+		RETIRED BOOLEAN NOT NULL DEFAULT FALSE,
+		UNIQUE (ID_VALUE_TYPE, VALUE))`,
 
 		`CREATE TABLE IF NOT EXISTS PARAMS (
 		ID_PARAM SERIAL PRIMARY KEY,
@@ -40,24 +60,133 @@ func (do *DbOperator) CreateTables() error {
 		NAME VARCHAR(300) UNIQUE CHECK (LENGTH(NAME) > 0),
 		ID_PARENT_CLASS INTEGER REFERENCES CLASSES(ID_CLASS) ON DELETE CASCADE,
 		ID_EI INTEGER REFERENCES EI(ID_EI) ON DELETE SET DEFAULT,
+		VERSION INTEGER NOT NULL DEFAULT 1,
 		UNIQUE (ID_CLASS, ID_PARENT_CLASS))`,
 
 		`CREATE TABLE IF NOT EXISTS CLASS_PARAMS (
 		ID_CLASS_PARAM SERIAL PRIMARY KEY,
 		ID_CLASS INTEGER REFERENCES CLASSES(ID_CLASS) ON DELETE CASCADE,
 		ID_PARAM INTEGER REFERENCES PARAMS(ID_PARAM) ON DELETE CASCADE,
+		MIN_VALUE NUMERIC,
+		MAX_VALUE NUMERIC,
+		STEP NUMERIC CHECK (STEP > 0),
+		PRECISION INTEGER CHECK (PRECISION >= 0),
+		MAX_LENGTH INTEGER CHECK (MAX_LENGTH > 0),
+		REQUIRED BOOLEAN NOT NULL DEFAULT FALSE,
+		DEFAULT_VALUE JSONB,
+		CHECK (MIN_VALUE <= MAX_VALUE),
 		UNIQUE (ID_CLASS, ID_PARAM))`,
 
+		`CREATE TABLE IF NOT EXISTS CLASS_PARAM_OVERRIDES (
+		ID_CLASS INTEGER REFERENCES CLASSES(ID_CLASS) ON DELETE CASCADE,
+		ID_CLASS_PARAM INTEGER REFERENCES CLASS_PARAMS(ID_CLASS_PARAM) ON DELETE CASCADE,
+		MIN_VALUE NUMERIC,
+		MAX_VALUE NUMERIC,
+		STEP NUMERIC CHECK (STEP > 0),
+		PRECISION INTEGER CHECK (PRECISION >= 0),
+		MAX_LENGTH INTEGER CHECK (MAX_LENGTH > 0),
+		REQUIRED BOOLEAN,
+		DEFAULT_VALUE JSONB,
+		ID_DISPLAY_EI INTEGER REFERENCES EI(ID_EI) ON DELETE SET NULL,
+		UNIQUE (ID_CLASS, ID_CLASS_PARAM))`,
+
 		`CREATE TABLE IF NOT EXISTS PRODUCTS (
 		ID_PRODUCT SERIAL PRIMARY KEY,
 		NAME VARCHAR(300) UNIQUE CHECK (LENGTH(NAME) > 0),
-		ID_PARENT_CLASS INTEGER REFERENCES CLASSES(ID_CLASS) ON DELETE CASCADE)`,
+		ID_PARENT_CLASS INTEGER REFERENCES CLASSES(ID_CLASS) ON DELETE CASCADE,
+		VERSION INTEGER NOT NULL DEFAULT 1)`,
 
 		`CREATE TABLE IF NOT EXISTS PRODUCT_PARAM_VALUES (
 		ID_PRODUCT INTEGER REFERENCES PRODUCTS(ID_PRODUCT) ON DELETE CASCADE,
 		ID_PARAM INTEGER REFERENCES CLASS_PARAMS(ID_CLASS_PARAM) ON DELETE CASCADE,
-		VALUE VARCHAR(300),
+		VALUE_INTEGER BIGINT,
+		VALUE_DECIMAL NUMERIC,
+		VALUE_BOOLEAN BOOLEAN,
+		VALUE_DATE DATE,
+		VALUE_STRING VARCHAR(300),
+		VALUE_ENUM INTEGER REFERENCES ENUM_VALUES(ID_ENUM_VALUE),
+		CHECK (NUM_NONNULLS(VALUE_INTEGER, VALUE_DECIMAL, VALUE_BOOLEAN, VALUE_DATE, VALUE_STRING, VALUE_ENUM) = 1),
 		UNIQUE (ID_PRODUCT, ID_PARAM))`,
+
+		// CLASSIFICATION_CODES keeps the codes of the classification standards the items were imported from
+		`CREATE TABLE IF NOT EXISTS CLASSIFICATION_CODES (
+		SYSTEM VARCHAR(100) CHECK (LENGTH(SYSTEM) > 0),
+		KIND VARCHAR(20),
+		CODE VARCHAR(100) CHECK (LENGTH(CODE) > 0),
+		ID INTEGER NOT NULL,
+		PRIMARY KEY (SYSTEM, KIND, CODE))`,
+
+		// the tables of the first schema get the columns that came later, the checks are
+		// created again as the older ones allow less base types and value columns
+		`ALTER TABLE EI
+			ADD COLUMN IF NOT EXISTS ID_DIMENSION INTEGER REFERENCES DIMENSIONS(ID_DIMENSION) ON DELETE SET NULL,
+			ADD COLUMN IF NOT EXISTS FACTOR NUMERIC NOT NULL DEFAULT 1 CHECK (FACTOR <> 0),
+			ADD COLUMN IF NOT EXISTS OFFSET_VALUE NUMERIC NOT NULL DEFAULT 0`,
+		`ALTER TABLE VALUE_TYPES
+			ADD COLUMN IF NOT EXISTS BASE_TYPE VARCHAR(20) NOT NULL DEFAULT 'string',
+			DROP CONSTRAINT IF EXISTS VALUE_TYPES_BASE_TYPE_CHECK,
+			ADD CONSTRAINT VALUE_TYPES_BASE_TYPE_CHECK
+				CHECK (BASE_TYPE IN ('integer', 'decimal', 'boolean', 'date', 'string', 'enum'))`,
+		`ALTER TABLE CLASS_PARAMS
+			ADD COLUMN IF NOT EXISTS MIN_VALUE NUMERIC,
+			ADD COLUMN IF NOT EXISTS MAX_VALUE NUMERIC,
+			ADD COLUMN IF NOT EXISTS STEP NUMERIC CHECK (STEP > 0),
+			ADD COLUMN IF NOT EXISTS PRECISION INTEGER CHECK (PRECISION >= 0),
+			ADD COLUMN IF NOT EXISTS MAX_LENGTH INTEGER CHECK (MAX_LENGTH > 0),
+			ADD COLUMN IF NOT EXISTS REQUIRED BOOLEAN NOT NULL DEFAULT FALSE,
+			ADD COLUMN IF NOT EXISTS DEFAULT_VALUE JSONB,
+			DROP CONSTRAINT IF EXISTS CLASS_PARAMS_CHECK,
+			ADD CONSTRAINT CLASS_PARAMS_CHECK CHECK (MIN_VALUE <= MAX_VALUE)`,
+		`ALTER TABLE PRODUCT_PARAM_VALUES
+			ADD COLUMN IF NOT EXISTS VALUE_INTEGER BIGINT,
+			ADD COLUMN IF NOT EXISTS VALUE_DECIMAL NUMERIC,
+			ADD COLUMN IF NOT EXISTS VALUE_BOOLEAN BOOLEAN,
+			ADD COLUMN IF NOT EXISTS VALUE_DATE DATE,
+			ADD COLUMN IF NOT EXISTS VALUE_STRING VARCHAR(300),
+			ADD COLUMN IF NOT EXISTS VALUE_ENUM INTEGER REFERENCES ENUM_VALUES(ID_ENUM_VALUE),
+			DROP CONSTRAINT IF EXISTS PRODUCT_PARAM_VALUES_CHECK`,
+		// the values of the first schema were untyped, so they become the values of string params
+		`DO $$
+		BEGIN
+			IF EXISTS (SELECT 1
+					FROM INFORMATION_SCHEMA.COLUMNS
+					WHERE TABLE_SCHEMA = CURRENT_SCHEMA() AND TABLE_NAME = 'product_param_values' AND COLUMN_NAME = 'value') THEN
+				DELETE FROM PRODUCT_PARAM_VALUES
+					WHERE VALUE IS NULL AND NUM_NONNULLS(VALUE_INTEGER, VALUE_DECIMAL, VALUE_BOOLEAN, VALUE_DATE, VALUE_STRING, VALUE_ENUM) = 0;
+				UPDATE PRODUCT_PARAM_VALUES
+					SET VALUE_STRING = VALUE
+					WHERE VALUE IS NOT NULL AND NUM_NONNULLS(VALUE_INTEGER, VALUE_DECIMAL, VALUE_BOOLEAN, VALUE_DATE, VALUE_STRING, VALUE_ENUM) = 0;
+				ALTER TABLE PRODUCT_PARAM_VALUES DROP COLUMN VALUE;
+			END IF;
+		END $$`,
+		`ALTER TABLE PRODUCT_PARAM_VALUES
+			ADD CONSTRAINT PRODUCT_PARAM_VALUES_CHECK
+				CHECK (NUM_NONNULLS(VALUE_INTEGER, VALUE_DECIMAL, VALUE_BOOLEAN, VALUE_DATE, VALUE_STRING, VALUE_ENUM) = 1)`,
+
+		// the versions came after the first tables were created
+		`ALTER TABLE CLASSES ADD COLUMN IF NOT EXISTS VERSION INTEGER NOT NULL DEFAULT 1`,
+		`ALTER TABLE PRODUCTS ADD COLUMN IF NOT EXISTS VERSION INTEGER NOT NULL DEFAULT 1`,
+
+		`CREATE OR REPLACE VIEW PRODUCT_VALUES AS
+		SELECT PPV.ID_PRODUCT, P.ID_PARAM, P.NAME AS PARAM_NAME, VT.BASE_TYPE,
+			PPV.VALUE_INTEGER, PPV.VALUE_DECIMAL, PPV.VALUE_BOOLEAN, PPV.VALUE_DATE,
+			COALESCE(PPV.VALUE_STRING, EV.VALUE) AS VALUE_STRING
+		FROM PRODUCT_PARAM_VALUES PPV JOIN CLASS_PARAMS CP ON PPV.ID_PARAM = CP.ID_CLASS_PARAM
+									JOIN PARAMS P ON P.ID_PARAM = CP.ID_PARAM
+									JOIN VALUE_TYPES VT ON P.ID_VALUE_TYPE = VT.ID_VALUE_TYPE
+									LEFT JOIN ENUM_VALUES EV ON EV.ID_ENUM_VALUE = PPV.VALUE_ENUM`,
+
+		`CREATE INDEX IF NOT EXISTS CLASSES_NAME_TRGM ON CLASSES USING GIN (NAME GIN_TRGM_OPS)`,
+		`CREATE INDEX IF NOT EXISTS PRODUCTS_NAME_TRGM ON PRODUCTS USING GIN (NAME GIN_TRGM_OPS)`,
+		`CREATE INDEX IF NOT EXISTS PRODUCT_PARAM_VALUES_STRING_TRGM ON PRODUCT_PARAM_VALUES USING GIN (VALUE_STRING GIN_TRGM_OPS)`,
+		`CREATE INDEX IF NOT EXISTS ENUM_VALUES_VALUE_TRGM ON ENUM_VALUES USING GIN (VALUE GIN_TRGM_OPS)`,
+		`CREATE INDEX IF NOT EXISTS PARAMS_NAME_TRGM ON PARAMS USING GIN (NAME GIN_TRGM_OPS)`,
+		// the search matches TO_TSVECTOR('simple', ...) of the same columns
+		`CREATE INDEX IF NOT EXISTS CLASSES_NAME_TSV ON CLASSES USING GIN (TO_TSVECTOR('simple', NAME))`,
+		`CREATE INDEX IF NOT EXISTS PRODUCTS_NAME_TSV ON PRODUCTS USING GIN (TO_TSVECTOR('simple', NAME))`,
+		`CREATE INDEX IF NOT EXISTS PARAMS_NAME_TSV ON PARAMS USING GIN (TO_TSVECTOR('simple', NAME))`,
+		`CREATE INDEX IF NOT EXISTS PRODUCT_PARAM_VALUES_STRING_TSV ON PRODUCT_PARAM_VALUES USING GIN (TO_TSVECTOR('simple', VALUE_STRING))`,
+		`CREATE INDEX IF NOT EXISTS ENUM_VALUES_VALUE_TSV ON ENUM_VALUES USING GIN (TO_TSVECTOR('simple', VALUE))`,
 	}
 	f := func(tx pgx.Tx) error {
 		for _, t := range tables {
@@ -76,7 +205,7 @@ func (do *DbOperator) CreateAndReadEIs(eis []*internal.EI) ([]int, error) {
 	var ids []int
 	f := func(tx pgx.Tx) error {
 		for _, ei := range eis {
-			eiId, err := do.cr_EI(tx, ei.Name, ei.ShortName)
+			eiId, err := do.cr_EI(tx, ei)
 			if err != nil {
 				return err
 			}
@@ -87,48 +216,110 @@ func (do *DbOperator) CreateAndReadEIs(eis []*internal.EI) ([]int, error) {
 	return ids, do.cs.WrapIntoTransaction(context.Background(), f)
 }
 
-func (do *DbOperator) ReadEI(searchName string) ([]*internal.EI, error) {
+// ReadEI reads the EIs with the name or the requested page of all EIs with their total count
+func (do *DbOperator) ReadEI(searchName string, page *Page) ([]*internal.EI, int, error) {
 	var res []*internal.EI
+	var total int
 	f := func(tx pgx.Tx) error {
-		eis, err := do.r_EI(tx, searchName)
+		eis, err := do.r_EI(tx, searchName, page)
 		if err != nil {
 			return err
 		}
 		res = eis
-		return nil
+		if searchName != "" {
+			total = len(eis)
+			return nil
+		}
+		return tx.QueryRow(context.Background(),
+			`SELECT COUNT(*)
+				FROM EI`).Scan(&total)
 	}
-	return res, do.cs.WrapIntoTransaction(context.Background(), f)
+	return res, total, do.cs.WrapIntoTransaction(context.Background(), f)
 }
 
-func (do *DbOperator) cr_EI(tx pgx.Tx, name, shortName string) (id int, err error) {
+func (do *DbOperator) cr_EI(tx pgx.Tx, ei *internal.EI) (id int, err error) {
 	err = tx.QueryRow(context.Background(),
 		`SELECT ID_EI 
 			FROM EI 
 			WHERE NAME = $1`,
-		name).Scan(&id)
+		ei.Name).Scan(&id)
 	if err != nil {
+		var idDimension sql.NullInt32
+		if ei.Dimension != "" {
+			var idDim int
+			if idDim, err = do.cr_Dimension(tx, ei.Dimension); err != nil {
+				return
+			}
+			idDimension = sql.NullInt32{Int32: int32(idDim), Valid: true}
+		}
+		factor := ei.Factor
+		if factor == 0 {
+			factor = 1
+		}
 		err = tx.QueryRow(context.Background(),
-			`INSERT INTO EI(NAME, SHORT_NAME) 
-				VALUES($1,$2) 
+			`INSERT INTO EI(NAME, SHORT_NAME, ID_DIMENSION, FACTOR, OFFSET_VALUE) 
+				VALUES($1,$2,$3,$4,$5) 
 				RETURNING ID_EI`,
-			name, shortName).Scan(&id)
+			ei.Name, ei.ShortName, idDimension, factor, ei.Offset).Scan(&id)
 	}
 	return
 }
 
-func (do *DbOperator) r_EI(tx pgx.Tx, searchName string) ([]*internal.EI, error) {
+// UpdateEI changes the short name, the dimension and the conversion of the EI with the name,
+// the values stored in it are kept as they are
+func (do *DbOperator) UpdateEI(name string, ei *internal.EI) error {
+	f := func(tx pgx.Tx) error {
+		return do.u_EI(tx, name, ei)
+	}
+	return do.cs.WrapIntoTransaction(context.Background(), f)
+}
+
+func (do *DbOperator) u_EI(tx pgx.Tx, name string, ei *internal.EI) error {
+	var idDimension sql.NullInt32
+	if ei.Dimension != "" {
+		idDim, err := do.cr_Dimension(tx, ei.Dimension)
+		if err != nil {
+			return err
+		}
+		idDimension = sql.NullInt32{Int32: int32(idDim), Valid: true}
+	}
+	factor := ei.Factor
+	if factor == 0 {
+		factor = 1
+	}
+	tag, err := tx.Exec(context.Background(),
+		`UPDATE EI
+			SET SHORT_NAME = $2, ID_DIMENSION = $3, FACTOR = $4, OFFSET_VALUE = $5
+			WHERE NAME = $1`,
+		name, ei.ShortName, idDimension, factor, ei.Offset)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (do *DbOperator) r_EI(tx pgx.Tx, searchName string, page *Page) ([]*internal.EI, error) {
+	order, err := page.orderBy(map[string]string{
+		"id":   "EI.ID_EI",
+		"name": "EI.NAME",
+	}, "id")
+	if err != nil {
+		return nil, err
+	}
 	var rows pgx.Rows
-	var err error
 	if searchName != "" {
 		rows, err = tx.Query(context.Background(),
-			`SELECT ID_EI, NAME, SHORT_NAME 
-				FROM EI 
-				WHERE NAME = $1`,
+			`SELECT EI.ID_EI, EI.NAME, EI.SHORT_NAME, D.NAME, EI.FACTOR::FLOAT8, EI.OFFSET_VALUE::FLOAT8 
+				FROM EI LEFT JOIN DIMENSIONS D ON EI.ID_DIMENSION = D.ID_DIMENSION
+				WHERE EI.NAME = $1`+order+page.limit(),
 			searchName)
 	} else {
 		rows, err = tx.Query(context.Background(),
-			`SELECT ID_EI, NAME, SHORT_NAME 
-				FROM EI`)
+			`SELECT EI.ID_EI, EI.NAME, EI.SHORT_NAME, D.NAME, EI.FACTOR::FLOAT8, EI.OFFSET_VALUE::FLOAT8 
+				FROM EI LEFT JOIN DIMENSIONS D ON EI.ID_DIMENSION = D.ID_DIMENSION`+order+page.limit())
 	}
 	if err != nil {
 		return nil, err
@@ -136,8 +327,10 @@ func (do *DbOperator) r_EI(tx pgx.Tx, searchName string) ([]*internal.EI, error)
 	var result []*internal.EI
 	var id int
 	var name, shortName string
+	var dimension sql.NullString
+	var factor, offset float64
 	for rows.Next() {
-		if err := rows.Scan(&id, &name, &shortName); err != nil {
+		if err := rows.Scan(&id, &name, &shortName, &dimension, &factor, &offset); err != nil {
 			rows.Close()
 			return nil, err
 		}
@@ -145,6 +338,9 @@ func (do *DbOperator) r_EI(tx pgx.Tx, searchName string) ([]*internal.EI, error)
 			Id:        id,
 			Name:      name,
 			ShortName: shortName,
+			Dimension: dimension.String,
+			Factor:    factor,
+			Offset:    offset,
 		})
 	}
 	rows.Close()
@@ -156,7 +352,7 @@ func (do *DbOperator) r_EI(tx pgx.Tx, searchName string) ([]*internal.EI, error)
 
 // VALUE_TYPES
 
-func (do *DbOperator) CreateValueTypes(vts []string) (err error) {
+func (do *DbOperator) CreateValueTypes(vts []*internal.ValueType) (err error) {
 	f := func(tx pgx.Tx) error {
 		for _, vt := range vts {
 			if err := do.c_ValueType(tx, vt); err != nil {
@@ -168,41 +364,179 @@ func (do *DbOperator) CreateValueTypes(vts []string) (err error) {
 	return do.cs.WrapIntoTransaction(context.Background(), f)
 }
 
-func (do *DbOperator) ReadValueTypes() (vts []string, err error) {
+func (do *DbOperator) ReadValueTypes(page *Page) (vts []*internal.ValueType, total int, err error) {
 	f := func(tx pgx.Tx) error {
-		vts, err = do.r_ValueType(tx)
+		vts, err = do.r_ValueType(tx, page)
 		if err != nil {
 			return err
 		}
-		return nil
+		return tx.QueryRow(context.Background(),
+			`SELECT COUNT(*)
+				FROM VALUE_TYPES`).Scan(&total)
 	}
-	return vts, do.cs.WrapIntoTransaction(context.Background(), f)
+	return vts, total, do.cs.WrapIntoTransaction(context.Background(), f)
 }
 
-func (do *DbOperator) c_ValueType(tx pgx.Tx, name string) error {
-	_, err := tx.Exec(context.Background(),
-		`INSERT INTO VALUE_TYPES(NAME)
-			VALUES($1)`,
-		name)
-	return err
+func (do *DbOperator) c_ValueType(tx pgx.Tx, vt *internal.ValueType) error {
+	baseType := vt.BaseType
+	if baseType == "" {
+		baseType = internal.BaseTypeString
+	}
+	if !isBaseType(baseType) {
+		return newInvalidErr("unknown base type %q", baseType)
+	}
+	if len(vt.Values) != 0 && baseType != internal.BaseTypeEnum {
+		return newInvalidErr("value type %s is not an enum", vt.Name)
+	}
+	var id int
+	if err := tx.QueryRow(context.Background(),
+		`INSERT INTO VALUE_TYPES(NAME, BASE_TYPE)
+			VALUES($1,$2)
+			RETURNING ID_VALUE_TYPE`,
+		vt.Name, baseType).Scan(&id); err != nil {
+		return err
+	}
+	values := make([]string, 0, len(vt.Values))
+	for _, v := range vt.Values {
+		values = append(values, v.Value)
+	}
+	return do.c_EnumValues(tx, id, values)
+}
+
+func (do *DbOperator) r_ValueTypeId(tx pgx.Tx, name string) (id int, baseType string, err error) {
+	err = tx.QueryRow(context.Background(),
+		`SELECT ID_VALUE_TYPE, BASE_TYPE
+			FROM VALUE_TYPES
+			WHERE NAME = $1`,
+		name).Scan(&id, &baseType)
+	return
 }
 
-func (do *DbOperator) r_ValueType(tx pgx.Tx) ([]string, error) {
+func (do *DbOperator) r_ValueType(tx pgx.Tx, page *Page) ([]*internal.ValueType, error) {
+	order, err := page.orderBy(map[string]string{
+		"id":   "ID_VALUE_TYPE",
+		"name": "NAME",
+	}, "id")
+	if err != nil {
+		return nil, err
+	}
 	rows, err := tx.Query(context.Background(),
-		`SELECT NAME 
-				FROM VALUE_TYPES`,
+		`SELECT ID_VALUE_TYPE, NAME, BASE_TYPE 
+				FROM VALUE_TYPES`+order+page.limit(),
 	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var result []string
-	var name string
+	var result []*internal.ValueType
+	var id int
+	var name, baseType string
+	for rows.Next() {
+		if err := rows.Scan(&id, &name, &baseType); err != nil {
+			return nil, err
+		}
+		result = append(result, &internal.ValueType{
+			Id:       id,
+			Name:     name,
+			BaseType: baseType,
+		})
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+	values, err := do.r_EnumValues(tx)
+	if err != nil {
+		return nil, err
+	}
+	for _, vt := range result {
+		vt.Values = values[vt.Id]
+	}
+	return result, nil
+}
+
+// ENUM_VALUES
+
+func (do *DbOperator) AddEnumValues(valueType string, values []string) error {
+	f := func(tx pgx.Tx) error {
+		id, err := do.r_EnumTypeId(tx, valueType)
+		if err != nil {
+			return err
+		}
+		return do.c_EnumValues(tx, id, values)
+	}
+	return do.cs.WrapIntoTransaction(context.Background(), f)
+}
+
+func (do *DbOperator) RenameEnumValue(valueType, value, newValue string) error {
+	f := func(tx pgx.Tx) error {
+		id, err := do.r_EnumTypeId(tx, valueType)
+		if err != nil {
+			return err
+		}
+		return do.u_EnumValue(tx, id, value, newValue)
+	}
+	return do.cs.WrapIntoTransaction(context.Background(), f)
+}
+
+func (do *DbOperator) RetireEnumValue(valueType, value string) error {
+	f := func(tx pgx.Tx) error {
+		id, err := do.r_EnumTypeId(tx, valueType)
+		if err != nil {
+			return err
+		}
+		return do.d_EnumValue(tx, id, value)
+	}
+	return do.cs.WrapIntoTransaction(context.Background(), f)
+}
+
+func (do *DbOperator) r_EnumTypeId(tx pgx.Tx, valueType string) (int, error) {
+	id, baseType, err := do.r_ValueTypeId(tx, valueType)
+	if err != nil {
+		return 0, err
+	}
+	if baseType != internal.BaseTypeEnum {
+		return 0, newInvalidErr("value type %s is not an enum", valueType)
+	}
+	return id, nil
+}
+
+func (do *DbOperator) c_EnumValues(tx pgx.Tx, idValueType int, values []string) error {
+	for _, v := range values {
+		_, err := tx.Exec(context.Background(),
+			`INSERT INTO ENUM_VALUES(ID_VALUE_TYPE, VALUE)
+				VALUES($1,$2)`,
+			idValueType, v)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// r_EnumValues returns the values of all enum types grouped by value type id
+func (do *DbOperator) r_EnumValues(tx pgx.Tx) (map[int][]*internal.EnumValue, error) {
+	rows, err := tx.Query(context.Background(),
+		`SELECT ID_ENUM_VALUE, ID_VALUE_TYPE, VALUE, RETIRED
+			FROM ENUM_VALUES
+			ORDER BY ID_ENUM_VALUE`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := make(map[int][]*internal.EnumValue)
+	var id, idValueType int
+	var value string
+	var retired bool
 	for rows.Next() {
-		if err := rows.Scan(&name); err != nil {
+		if err := rows.Scan(&id, &idValueType, &value, &retired); err != nil {
 			return nil, err
 		}
-		result = append(result, name)
+		result[idValueType] = append(result[idValueType], &internal.EnumValue{
+			Id:      id,
+			Value:   value,
+			Retired: retired,
+		})
 	}
 	if err = rows.Err(); err != nil {
 		return nil, err
@@ -210,22 +544,165 @@ func (do *DbOperator) r_ValueType(tx pgx.Tx) ([]string, error) {
 	return result, nil
 }
 
+// r_EnumValueId finds the id of an enum value that can be used for new product values
+func (do *DbOperator) r_EnumValueId(tx pgx.Tx, valueType, value string) (id int, err error) {
+	var retired bool
+	err = tx.QueryRow(context.Background(),
+		`SELECT EV.ID_ENUM_VALUE, EV.RETIRED
+			FROM ENUM_VALUES EV JOIN VALUE_TYPES VT ON EV.ID_VALUE_TYPE = VT.ID_VALUE_TYPE
+			WHERE VT.NAME = $1 AND EV.VALUE = $2`,
+		valueType, value).Scan(&id, &retired)
+	if err == pgx.ErrNoRows {
+		return 0, newInvalidErr("%q is not one of the allowed values of %s", value, valueType)
+	}
+	if err != nil {
+		return
+	}
+	if retired {
+		return 0, newInvalidErr("%q is retired and can't be used anymore", value)
+	}
+	return
+}
+
+func (do *DbOperator) u_EnumValue(tx pgx.Tx, idValueType int, value, newValue string) error {
+	tag, err := tx.Exec(context.Background(),
+		`UPDATE ENUM_VALUES
+			SET VALUE = $3
+			WHERE ID_VALUE_TYPE = $1 AND VALUE = $2`,
+		idValueType, value, newValue)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// d_EnumValue only retires the value, products that already use it keep it
+func (do *DbOperator) d_EnumValue(tx pgx.Tx, idValueType int, value string) error {
+	tag, err := tx.Exec(context.Background(),
+		`UPDATE ENUM_VALUES
+			SET RETIRED = TRUE
+			WHERE ID_VALUE_TYPE = $1 AND VALUE = $2`,
+		idValueType, value)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
 // PARAMS
 
+func (do *DbOperator) CreateParams(pp []*internal.Param) ([]int, error) {
+	var ids []int
+	f := func(tx pgx.Tx) error {
+		for _, p := range pp {
+			id, err := do.cr_Param(tx, p)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+		return nil
+	}
+	return ids, do.cs.WrapIntoTransaction(context.Background(), f)
+}
+
+func (do *DbOperator) ReadParams(searchName string) ([]*internal.Param, error) {
+	var res []*internal.Param
+	f := func(tx pgx.Tx) error {
+		pp, err := do.r_Params(tx, searchName)
+		if err != nil {
+			return err
+		}
+		res = pp
+		return nil
+	}
+	return res, do.cs.WrapIntoTransaction(context.Background(), f)
+}
+
+func (do *DbOperator) ReadParamClasses(id int) ([]*internal.Class, error) {
+	var res []*internal.Class
+	f := func(tx pgx.Tx) error {
+		cc, err := do.r_ParamClasses(tx, id)
+		if err != nil {
+			return err
+		}
+		res = cc
+		return nil
+	}
+	return res, do.cs.WrapIntoTransaction(context.Background(), f)
+}
+
+// cr_Param finds the param in the dictionary by its id or name and creates it if it isn't there,
+// p is filled with the dictionary definition
+func (do *DbOperator) cr_Param(tx pgx.Tx, p *internal.Param) (id int, err error) {
+	var existing *internal.Param
+	if p.Id != 0 {
+		existing, err = do.r_Param(tx, p.Id)
+		if err == pgx.ErrNoRows {
+			return 0, newInvalidErr("couldn't find param %d", p.Id)
+		}
+		if err != nil {
+			return
+		}
+	} else {
+		var pp []*internal.Param
+		if pp, err = do.r_Params(tx, p.Name); err != nil {
+			return
+		}
+		if len(pp) != 0 {
+			existing = pp[0]
+		}
+	}
+	if existing == nil {
+		if id, err = do.c_Param(tx, p); err != nil {
+			return
+		}
+		if existing, err = do.r_Param(tx, id); err != nil {
+			return
+		}
+	} else if (p.ValType != "" && p.ValType != existing.ValType) ||
+		(p.EI != nil && p.EI.Name != "" && p.EI.Name != existing.EI.Name) {
+		return 0, newConflictErr("param %s already exists with another value type or ei", existing.Name)
+	}
+	p.Id = existing.Id
+	p.Name = existing.Name
+	p.ValType = existing.ValType
+	p.BaseType = existing.BaseType
+	p.EI = existing.EI
+	return existing.Id, nil
+}
+
 func (do *DbOperator) c_Param(tx pgx.Tx, p *internal.Param) (id int, err error) {
+	if p.ValType == "" || p.EI == nil {
+		return 0, newInvalidErr("value type and ei are needed to create param %s", p.Name)
+	}
 	var idValueType, idEi int
-	if err = tx.QueryRow(context.Background(),
+	err = tx.QueryRow(context.Background(),
 		`SELECT ID_VALUE_TYPE 
 			FROM VALUE_TYPES 
 			WHERE NAME = $1`,
-		p.ValType).Scan(&idValueType); err != nil {
+		p.ValType).Scan(&idValueType)
+	if err == pgx.ErrNoRows {
+		return 0, newInvalidErr("couldn't find value type %s of param %s", p.ValType, p.Name)
+	}
+	if err != nil {
 		return
 	}
-	if err = tx.QueryRow(context.Background(),
+	err = tx.QueryRow(context.Background(),
 		`SELECT ID_EI 
 			FROM EI 
 			WHERE NAME = $1`,
-		p.EI.Name).Scan(&idEi); err != nil {
+		p.EI.Name).Scan(&idEi)
+	if err == pgx.ErrNoRows {
+		return 0, newInvalidErr("couldn't find ei %s of param %s", p.EI.Name, p.Name)
+	}
+	if err != nil {
 		return
 	}
 	err = tx.QueryRow(context.Background(),
@@ -235,6 +712,85 @@ func (do *DbOperator) c_Param(tx pgx.Tx, p *internal.Param) (id int, err error)
 	return
 }
 
+func (do *DbOperator) r_Param(tx pgx.Tx, id int) (*internal.Param, error) {
+	p := &internal.Param{EI: &internal.EI{}}
+	err := tx.QueryRow(context.Background(),
+		`SELECT P.ID_PARAM, P.NAME, VT.NAME, VT.BASE_TYPE, EI.NAME, EI.SHORT_NAME
+			FROM PARAMS P JOIN VALUE_TYPES VT ON P.ID_VALUE_TYPE = VT.ID_VALUE_TYPE
+						JOIN EI ON P.ID_EI = EI.ID_EI
+			WHERE P.ID_PARAM = $1`,
+		id).Scan(&p.Id, &p.Name, &p.ValType, &p.BaseType, &p.EI.Name, &p.EI.ShortName)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (do *DbOperator) r_Params(tx pgx.Tx, searchName string) ([]*internal.Param, error) {
+	var rows pgx.Rows
+	var err error
+	if searchName != "" {
+		rows, err = tx.Query(context.Background(),
+			`SELECT P.ID_PARAM, P.NAME, VT.NAME, VT.BASE_TYPE, EI.NAME, EI.SHORT_NAME
+				FROM PARAMS P JOIN VALUE_TYPES VT ON P.ID_VALUE_TYPE = VT.ID_VALUE_TYPE
+							JOIN EI ON P.ID_EI = EI.ID_EI
+				WHERE P.NAME = $1`,
+			searchName)
+	} else {
+		rows, err = tx.Query(context.Background(),
+			`SELECT P.ID_PARAM, P.NAME, VT.NAME, VT.BASE_TYPE, EI.NAME, EI.SHORT_NAME
+				FROM PARAMS P JOIN VALUE_TYPES VT ON P.ID_VALUE_TYPE = VT.ID_VALUE_TYPE
+							JOIN EI ON P.ID_EI = EI.ID_EI
+				ORDER BY P.ID_PARAM`)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var result []*internal.Param
+	for rows.Next() {
+		p := &internal.Param{EI: &internal.EI{}}
+		if err := rows.Scan(&p.Id, &p.Name, &p.ValType, &p.BaseType, &p.EI.Name, &p.EI.ShortName); err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// r_ParamClasses returns the classes the param is attached to directly
+func (do *DbOperator) r_ParamClasses(tx pgx.Tx, id int) ([]*internal.Class, error) {
+	rows, err := tx.Query(context.Background(),
+		`SELECT C.ID_CLASS, C.NAME
+			FROM CLASS_PARAMS CP JOIN CLASSES C ON CP.ID_CLASS = C.ID_CLASS
+			WHERE CP.ID_PARAM = $1
+			ORDER BY C.ID_CLASS`,
+		id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := []*internal.Class{}
+	var idClass int
+	var name string
+	for rows.Next() {
+		if err := rows.Scan(&idClass, &name); err != nil {
+			return nil, err
+		}
+		result = append(result, &internal.Class{
+			Id:   idClass,
+			Name: name,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 // CLASSES
 
 func (do *DbOperator) c_Class(tx pgx.Tx, c *internal.Class, parentClass sql.NullInt32) (id int, err error) {
@@ -245,12 +801,15 @@ func (do *DbOperator) c_Class(tx pgx.Tx, c *internal.Class, parentClass sql.Null
 			return
 		}
 	}
-	ei, err := do.r_EI(tx, c.Ei.Name)
+	if c.Ei == nil {
+		return 0, newInvalidErr("class %s has no ei", c.Name)
+	}
+	ei, err := do.r_EI(tx, c.Ei.Name, nil)
 	if err != nil {
 		return
 	}
 	if len(ei) == 0 {
-		return 0, errors.New("couldn't find ei")
+		return 0, newInvalidErr("couldn't find ei %s", c.Ei.Name)
 	}
 	err = tx.QueryRow(context.Background(),
 		`INSERT INTO CLASSES(NAME, ID_PARENT_CLASS, ID_EI) 
@@ -263,6 +822,9 @@ func (do *DbOperator) c_Class(tx pgx.Tx, c *internal.Class, parentClass sql.Null
 	if err = do.c_ClassParams(tx, c); err != nil {
 		return
 	}
+	if err = do.c_ParamOverrides(tx, id, c.Overrides); err != nil {
+		return
+	}
 	for _, child := range c.Children {
 		_, err = do.c_Class(tx, child, sql.NullInt32{
 			Int32: int32(id),
@@ -298,16 +860,18 @@ func (do *DbOperator) r_ParentClass(tx pgx.Tx, name string) (id sql.NullInt32, e
 
 func (do *DbOperator) r_Class(tx pgx.Tx, idClass int, withParams bool) (*internal.Class, error) {
 	var name, eiName, eiShortName string
+	var version int
 	if err := tx.QueryRow(context.Background(),
-		`SELECT C.NAME, EIC.NAME, EIC.SHORT_NAME
+		`SELECT C.NAME, EIC.NAME, EIC.SHORT_NAME, C.VERSION
 			FROM CLASSES C JOIN EI EIC ON C.ID_EI = EIC.ID_EI
 			WHERE C.ID_CLASS = $1`,
-		idClass).Scan(&name, &eiName, &eiShortName); err != nil {
+		idClass).Scan(&name, &eiName, &eiShortName, &version); err != nil {
 		return nil, err
 	}
 	c := &internal.Class{
 		Id:       idClass,
 		Name:     name,
+		Version:  version,
 		Children: nil,
 		Ei: &internal.EI{
 			Id:        0,
@@ -332,7 +896,9 @@ func (do *DbOperator) r_Class(tx pgx.Tx, idClass int, withParams bool) (*interna
 					SELECT * FROM SUBCLASSES
 				) ITER ORDER BY ID_PARENT_CLASS NULLS FIRST
 			)
-			SELECT P.ID_PARAM, P.NAME, VT.NAME, EIP.NAME, EIP.SHORT_NAME, CP.ID_CLASS
+			SELECT P.ID_PARAM, P.NAME, VT.NAME, VT.BASE_TYPE, EIP.NAME, EIP.SHORT_NAME, CP.ID_CLASS,
+				CP.MIN_VALUE::FLOAT8, CP.MAX_VALUE::FLOAT8, CP.STEP::FLOAT8, CP.PRECISION, CP.MAX_LENGTH,
+				CP.REQUIRED, CP.DEFAULT_VALUE::TEXT
 			FROM CLASS_PARAMS CP JOIN CLASS_FAMILY CF ON CF.ID_CLASS = CP.ID_CLASS
 							JOIN PARAMS P ON CP.ID_PARAM = P.ID_PARAM
 							JOIN VALUE_TYPES VT ON P.ID_VALUE_TYPE = VT.ID_VALUE_TYPE
@@ -342,9 +908,19 @@ func (do *DbOperator) r_Class(tx pgx.Tx, idClass int, withParams bool) (*interna
 			return nil, err
 		}
 		var idParam, idParamOwner int
-		var paramName, valTypeName, eiParamName, eiParamShortName string
+		var paramName, valTypeName, baseType, eiParamName, eiParamShortName string
 		for rows.Next() {
-			if err = rows.Scan(&idParam, &paramName, &valTypeName, &eiParamName, &eiParamShortName, &idParamOwner); err != nil {
+			constraints := &internal.ParamConstraints{}
+			var required bool
+			var defaultValue sql.NullString
+			if err = rows.Scan(&idParam, &paramName, &valTypeName, &baseType, &eiParamName, &eiParamShortName, &idParamOwner,
+				&constraints.Min, &constraints.Max, &constraints.Step, &constraints.Precision, &constraints.MaxLength,
+				&required, &defaultValue); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			def, err := decodeDefault(defaultValue)
+			if err != nil {
 				rows.Close()
 				return nil, err
 			}
@@ -353,17 +929,24 @@ func (do *DbOperator) r_Class(tx pgx.Tx, idClass int, withParams bool) (*interna
 				Id:      idParam,
 				Name:    paramName,
 				ValType: valTypeName,
+				BaseType: baseType,
 				EI: &internal.EI{
 					Id:        0,
 					Name:      eiParamName,
 					ShortName: eiParamShortName,
 				},
+				Constraints: constraints,
+				Required:    required,
+				Default:     def,
 			})
 		}
 		rows.Close()
 		if err = rows.Err(); err != nil {
 			return nil, err
 		}
+		if err = do.applyParamOverrides(tx, c); err != nil {
+			return nil, err
+		}
 	}
 	return c, nil
 }
@@ -458,11 +1041,61 @@ func (do *DbOperator) r_ClassChildren(tx pgx.Tx, searchName string) (*internal.C
 	return initialClass, nil
 }
 
-func (do *DbOperator) u_Class(tx pgx.Tx, c *internal.Class) (id int, err error) {
-	if err = do.d_Class(tx, c.Id); err != nil {
-		return
+// u_Class changes the class in place, so its id, children and products are kept
+func (do *DbOperator) u_Class(tx pgx.Tx, id int, u *internal.ClassUpdate) error {
+	class, err := do.r_Class(tx, id, false)
+	if err != nil {
+		return err
+	}
+	if u.Name != "" && u.Name != class.Name {
+		if _, err := tx.Exec(context.Background(),
+			`UPDATE CLASSES
+				SET NAME = $2
+				WHERE ID_CLASS = $1`,
+			id, u.Name); err != nil {
+			return err
+		}
+		class.Name = u.Name
+	}
+	if u.Ei != nil {
+		ei, err := do.r_EI(tx, u.Ei.Name, nil)
+		if err != nil {
+			return err
+		}
+		if len(ei) == 0 {
+			return newInvalidErr("couldn't find ei %s", u.Ei.Name)
+		}
+		if _, err := tx.Exec(context.Background(),
+			`UPDATE CLASSES
+				SET ID_EI = $2
+				WHERE ID_CLASS = $1`,
+			id, ei[0].Id); err != nil {
+			return err
+		}
+	}
+	for _, name := range u.RemoveParams {
+		if err := do.d_ClassParam(tx, id, name, u.DropValues); err != nil {
+			return err
+		}
+	}
+	if err := do.c_ClassParams(tx, &internal.Class{Name: class.Name, Params: u.AddParams}); err != nil {
+		return err
+	}
+	if err := do.u_ParamOverrides(tx, id, u.SetOverrides, u.RemoveOverrides); err != nil {
+		return err
 	}
-	return do.c_Class(tx, c, sql.NullInt32{})
+	conflicts, err := do.r_SubtreeConflicts(tx, id)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) != 0 {
+		vErr := &ValidationError{}
+		for _, pc := range conflicts {
+			vErr.Fields = append(vErr.Fields, pc.Conflicts...)
+		}
+		return vErr
+	}
+	return nil
 }
 
 func (do *DbOperator) d_Class(tx pgx.Tx, id int) (err error) {
@@ -481,6 +1114,139 @@ func (do *DbOperator) d_Class(tx pgx.Tx, id int) (err error) {
 	return
 }
 
+// r_SubtreeIds returns the ids of the class and all of its descendants
+func (do *DbOperator) r_SubtreeIds(tx pgx.Tx, id int) ([]int, error) {
+	rows, err := tx.Query(context.Background(),
+		`WITH RECURSIVE SUBCLASSES AS (
+				SELECT ID_CLASS
+				FROM CLASSES
+				WHERE ID_CLASS = $1 UNION
+				SELECT C.ID_CLASS
+				FROM CLASSES C INNER JOIN SUBCLASSES S ON S.ID_CLASS = C.ID_PARENT_CLASS)
+			SELECT ID_CLASS FROM SUBCLASSES`,
+		id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []int
+	var idClass int
+	for rows.Next() {
+		if err := rows.Scan(&idClass); err != nil {
+			return nil, err
+		}
+		ids = append(ids, idClass)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// u_ClassParent moves the class with its subtree under another parent,
+// a zero parent makes the class a root
+func (do *DbOperator) u_ClassParent(tx pgx.Tx, id, idParent int) error {
+	subtree, err := do.r_SubtreeIds(tx, id)
+	if err != nil {
+		return err
+	}
+	if len(subtree) == 0 {
+		return pgx.ErrNoRows
+	}
+	parent := sql.NullInt32{}
+	if idParent != 0 {
+		for _, idSub := range subtree {
+			if idSub == idParent {
+				return newInvalidErr("can't move a class under itself or its descendant")
+			}
+		}
+		var exists bool
+		if err := tx.QueryRow(context.Background(),
+			`SELECT EXISTS (
+					SELECT 1
+					FROM CLASSES
+					WHERE ID_CLASS = $1)`,
+			idParent).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return newInvalidErr("couldn't find parent class %d", idParent)
+		}
+		var productsCount int
+		if err := tx.QueryRow(context.Background(),
+			`SELECT COUNT(*)
+				FROM PRODUCTS
+				WHERE ID_PARENT_CLASS = $1`,
+			idParent).Scan(&productsCount); err != nil {
+			return err
+		}
+		if productsCount != 0 {
+			return newConflictErr("can't move a class under a class with products")
+		}
+		var paramName string
+		err := tx.QueryRow(context.Background(),
+			`WITH RECURSIVE ANCESTORS AS (
+					SELECT ID_CLASS, ID_PARENT_CLASS
+					FROM CLASSES
+					WHERE ID_CLASS = $2 UNION
+					SELECT C.ID_CLASS, C.ID_PARENT_CLASS
+					FROM CLASSES C INNER JOIN ANCESTORS A ON A.ID_PARENT_CLASS = C.ID_CLASS)
+				SELECT P.NAME
+				FROM CLASS_PARAMS ACP JOIN CLASS_PARAMS SCP ON ACP.ID_PARAM = SCP.ID_PARAM
+								JOIN PARAMS P ON ACP.ID_PARAM = P.ID_PARAM
+				WHERE ACP.ID_CLASS IN (SELECT ID_CLASS FROM ANCESTORS) AND SCP.ID_CLASS = ANY($1)
+				LIMIT 1`,
+			subtree, idParent).Scan(&paramName)
+		if err == nil {
+			return newConflictErr("param %s would be inherited twice after the move", paramName)
+		}
+		if err != pgx.ErrNoRows {
+			return err
+		}
+		parent = sql.NullInt32{Int32: int32(idParent), Valid: true}
+	}
+	tag, err := tx.Exec(context.Background(),
+		`UPDATE CLASSES
+			SET ID_PARENT_CLASS = $2
+			WHERE ID_CLASS = $1`,
+		id, parent)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// r_SubtreeConflicts checks the products of the subtree against the params their classes have now
+func (do *DbOperator) r_SubtreeConflicts(tx pgx.Tx, id int) ([]*productConflicts, error) {
+	subtree, err := do.r_SubtreeIds(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	var result []*productConflicts
+	for _, idClass := range subtree {
+		products, err := do.r_ClassProducts(tx, idClass, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(products) == 0 {
+			continue
+		}
+		class, err := do.r_Class(tx, idClass, true)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range products {
+			if pc := checkStoredProduct(p, class); len(pc.Conflicts) != 0 {
+				result = append(result, pc)
+			}
+		}
+	}
+	return result, nil
+}
+
 func (do *DbOperator) CreateClasses(cc []*internal.Class) (err error) {
 	f := func(tx pgx.Tx) error {
 		for _, c := range cc {
@@ -533,8 +1299,91 @@ func (do *DbOperator) ReadClassChildren(searchName string) (*internal.Class, err
 	return c, do.cs.WrapIntoTransaction(context.Background(), f)
 }
 
-func (do *DbOperator) DeleteClass(id int) error {
+// ReadClassSubtree is ReadClassChildren by the class id
+func (do *DbOperator) ReadClassSubtree(id int) (*internal.Class, error) {
+	var c *internal.Class
+	f := func(tx pgx.Tx) error {
+		class, err := do.r_Class(tx, id, false)
+		if err != nil {
+			return err
+		}
+		if c, err = do.r_ClassChildren(tx, class.Name); err != nil {
+			return err
+		}
+		return nil
+	}
+	return c, do.cs.WrapIntoTransaction(context.Background(), f)
+}
+
+// MoveClass changes the parent of the class. The products of the moved subtree are checked against
+// the params they inherit after the move, when there are conflicts the move is only done with force,
+// which drops the values of the params that are not inherited anymore
+func (do *DbOperator) MoveClass(id, idParent int, force bool) (*MoveReport, error) {
+	report := &MoveReport{Conflicts: []*FieldError{}}
+	f := func(tx pgx.Tx) error {
+		if err := do.u_ClassParent(tx, id, idParent); err != nil {
+			return err
+		}
+		if err := do.u_ClassVersion(tx, id, nil); err != nil {
+			return err
+		}
+		conflicts, err := do.r_SubtreeConflicts(tx, id)
+		if err != nil {
+			return err
+		}
+		for _, pc := range conflicts {
+			report.Conflicts = append(report.Conflicts, pc.Conflicts...)
+		}
+		if len(report.Conflicts) != 0 && !force {
+			return errMoveConflicts
+		}
+		var unresolved []*FieldError
+		for _, pc := range conflicts {
+			unresolved = append(unresolved, pc.Unresolved...)
+		}
+		if len(unresolved) != 0 {
+			report.Conflicts = unresolved
+			return errMoveConflicts
+		}
+		for _, pc := range conflicts {
+			dropped := append(pc.Orphans, pc.Invalid...)
+			for _, pnv := range dropped {
+				if err := do.d_ProductParamValue(tx, pc.Product.Id, pnv.Param); err != nil {
+					return err
+				}
+			}
+			if len(dropped) != 0 {
+				if err := do.u_ProductVersion(tx, pc.Product.Id, nil); err != nil {
+					return err
+				}
+			}
+		}
+		report.Moved = true
+		return nil
+	}
+	err := do.cs.WrapIntoTransaction(context.Background(), f)
+	if errors.Is(err, errMoveConflicts) {
+		return report, nil
+	}
+	return report, err
+}
+
+// UpdateClass changes the class if the precondition holds for its version
+func (do *DbOperator) UpdateClass(id int, u *internal.ClassUpdate, pc *Precondition) error {
+	f := func(tx pgx.Tx) error {
+		if err := do.u_ClassVersion(tx, id, pc); err != nil {
+			return err
+		}
+		return do.u_Class(tx, id, u)
+	}
+	return do.cs.WrapIntoTransaction(context.Background(), f)
+}
+
+func (do *DbOperator) DeleteClass(id int, pc *Precondition) error {
 	f := func(tx pgx.Tx) error {
+		if err := do.u_ClassVersion(tx, id, pc); err != nil {
+			return err
+		}
 		if err := do.d_Class(tx, id); err != nil {
 			return err
 		}
@@ -551,14 +1400,35 @@ func (do *DbOperator) c_ClassParams(tx pgx.Tx, c *internal.Class) (err error) {
 		if err != nil {
 			return err
 		}
-		idParam, err := do.c_Param(tx, param)
+		if err := validateConstraints(param); err != nil {
+			return err
+		}
+		constraints := param.Constraints
+		if constraints == nil {
+			constraints = &internal.ParamConstraints{}
+		}
+		idParam, err := do.cr_Param(tx, param)
 		if err != nil {
 			return err
 		}
+		if err := do.checkParamNotInherited(tx, idClass, idParam); err != nil {
+			return err
+		}
+		var defaultValue sql.NullString
+		if param.Default != nil {
+			if _, err := do.checkValue(tx, param, param.Default, ""); err != nil {
+				return newInvalidErr("invalid default of param %s: %w", param.Name, err)
+			}
+			if defaultValue, err = encodeDefault(param.Default); err != nil {
+				return err
+			}
+		}
 		_, err = tx.Exec(context.Background(),
-			`INSERT INTO CLASS_PARAMS(ID_CLASS, ID_PARAM)
-				VALUES($1,$2)`,
-			idClass, idParam)
+			`INSERT INTO CLASS_PARAMS(ID_CLASS, ID_PARAM, MIN_VALUE, MAX_VALUE, STEP, PRECISION, MAX_LENGTH,
+					REQUIRED, DEFAULT_VALUE)
+				VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9)`,
+			idClass, idParam, constraints.Min, constraints.Max, constraints.Step, constraints.Precision, constraints.MaxLength,
+			param.Required, defaultValue)
 		if err != nil {
 			return err
 		}
@@ -566,30 +1436,151 @@ func (do *DbOperator) c_ClassParams(tx pgx.Tx, c *internal.Class) (err error) {
 	return
 }
 
-// PRODUCTS
-
-func (do *DbOperator) c_Product(tx pgx.Tx, p *internal.Product) (err error) {
-	class, err := do.r_ClassChildren(tx, p.ParentClass.Name)
+// d_ClassParam removes a param the class defines itself, the values products have
+// for it are only dropped when dropValues is set
+func (do *DbOperator) d_ClassParam(tx pgx.Tx, idClass int, name string, dropValues bool) error {
+	var idClassParam int
+	err := tx.QueryRow(context.Background(),
+		`SELECT CP.ID_CLASS_PARAM
+			FROM CLASS_PARAMS CP JOIN PARAMS P ON CP.ID_PARAM = P.ID_PARAM
+			WHERE CP.ID_CLASS = $1 AND P.NAME = $2`,
+		idClass, name).Scan(&idClassParam)
+	if err == pgx.ErrNoRows {
+		return newInvalidErr("class doesn't define param %s", name)
+	}
 	if err != nil {
 		return err
 	}
-	if class.Children == nil || len(class.Children) != 0 {
-		return errors.New("can't add product to non-terminal class")
+	var valuesCount int
+	if err := tx.QueryRow(context.Background(),
+		`SELECT COUNT(*)
+			FROM PRODUCT_PARAM_VALUES
+			WHERE ID_PARAM = $1`,
+		idClassParam).Scan(&valuesCount); err != nil {
+		return err
+	}
+	if valuesCount != 0 && !dropValues {
+		return newConflictErr("param %s has %d product values, they have to be dropped to remove it", name, valuesCount)
+	}
+	if valuesCount != 0 {
+		if _, err := tx.Exec(context.Background(),
+			`UPDATE PRODUCTS
+				SET VERSION = VERSION + 1
+				WHERE ID_PRODUCT IN (
+					SELECT ID_PRODUCT
+					FROM PRODUCT_PARAM_VALUES
+					WHERE ID_PARAM = $1)`,
+			idClassParam); err != nil {
+			return err
+		}
 	}
 	_, err = tx.Exec(context.Background(),
+		`DELETE FROM CLASS_PARAMS
+			WHERE ID_CLASS_PARAM = $1`,
+		idClassParam)
+	return err
+}
+
+// checkParamNotInherited makes sure the param isn't attached to an ancestor or a descendant of the class,
+// as a class can have a param only once
+func (do *DbOperator) checkParamNotInherited(tx pgx.Tx, idClass, idParam int) error {
+	var name string
+	err := tx.QueryRow(context.Background(),
+		`WITH RECURSIVE ANCESTORS AS (
+				SELECT ID_CLASS, ID_PARENT_CLASS
+				FROM CLASSES
+				WHERE ID_CLASS = $1 UNION
+				SELECT C.ID_CLASS, C.ID_PARENT_CLASS
+				FROM CLASSES C INNER JOIN ANCESTORS A ON A.ID_PARENT_CLASS = C.ID_CLASS),
+			SUBCLASSES AS (
+				SELECT ID_CLASS
+				FROM CLASSES
+				WHERE ID_CLASS = $1 UNION
+				SELECT C.ID_CLASS
+				FROM CLASSES C INNER JOIN SUBCLASSES S ON S.ID_CLASS = C.ID_PARENT_CLASS)
+			SELECT C.NAME
+			FROM CLASS_PARAMS CP JOIN CLASSES C ON CP.ID_CLASS = C.ID_CLASS
+			WHERE CP.ID_PARAM = $2 AND (
+				CP.ID_CLASS IN (SELECT ID_CLASS FROM ANCESTORS) OR
+				CP.ID_CLASS IN (SELECT ID_CLASS FROM SUBCLASSES))
+			LIMIT 1`,
+		idClass, idParam).Scan(&name)
+	if err == pgx.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return newConflictErr("param is already attached to the related class %s, use overrides instead", name)
+}
+
+// PRODUCTS
+
+func (do *DbOperator) c_Product(tx pgx.Tx, p *internal.Product) (id int, err error) {
+	if p.ParentClass == nil {
+		return 0, newInvalidErr("product %s has no parent class", p.Name)
+	}
+	terminal, err := do.r_ProductClass(tx, p.ParentClass)
+	if err != nil {
+		return 0, err
+	}
+	p.ParentClass = terminal
+	class, err := do.r_Class(tx, terminal.Id, true)
+	if err != nil {
+		return 0, err
+	}
+	return do.c_ClassProduct(tx, p, class)
+}
+
+// c_ClassProduct creates the product in the class that is already read with its effective params,
+// so the callers creating many products of a class read it once
+func (do *DbOperator) c_ClassProduct(tx pgx.Tx, p *internal.Product, class *internal.Class) (id int, err error) {
+	err = tx.QueryRow(context.Background(),
 		`INSERT INTO PRODUCTS(NAME, ID_PARENT_CLASS) 
 			VALUES($1,$2) 
 			RETURNING ID_PRODUCT`,
-		p.Name, class.Id)
+		p.Name, class.Id).Scan(&id)
 	if err != nil {
 		return
 	}
-	if err = do.c_ProductParams(tx, p); err != nil {
-		return
-	}
+	err = do.c_ProductParams(tx, id, p, class)
 	return
 }
 
+// r_TerminalClass finds the class products can be added to
+func (do *DbOperator) r_TerminalClass(tx pgx.Tx, name string) (*internal.Class, error) {
+	class, err := do.r_ClassChildren(tx, name)
+	if err != nil {
+		return nil, err
+	}
+	if class == nil {
+		return nil, newInvalidErr("couldn't find class %s", name)
+	}
+	if class.Children == nil || len(class.Children) != 0 {
+		return nil, newInvalidErr("can't add product to non-terminal class")
+	}
+	return class, nil
+}
+
+// r_ProductClass finds the terminal class a product refers to by the id if it is set and by the name otherwise
+func (do *DbOperator) r_ProductClass(tx pgx.Tx, ref *internal.Class) (*internal.Class, error) {
+	name := ref.Name
+	if ref.Id != 0 {
+		err := tx.QueryRow(context.Background(),
+			`SELECT NAME
+				FROM CLASSES
+				WHERE ID_CLASS = $1`,
+			ref.Id).Scan(&name)
+		if err == pgx.ErrNoRows {
+			return nil, newInvalidErr("couldn't find class %d", ref.Id)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return do.r_TerminalClass(tx, name)
+}
+
 func (do *DbOperator) r_ProductId(tx pgx.Tx, name string) (id int, err error) {
 	err = tx.QueryRow(context.Background(),
 		`SELECT ID_PRODUCT 
@@ -600,70 +1591,151 @@ func (do *DbOperator) r_ProductId(tx pgx.Tx, name string) (id int, err error) {
 }
 
 func (do *DbOperator) r_Product(tx pgx.Tx, id int) (*internal.Product, error) {
-	var name string
-	var idParent int
-	if err := tx.QueryRow(context.Background(),
-		`SELECT NAME, ID_PARENT_CLASS
+	pp, err := do.r_Products(tx, []int{id})
+	if err != nil {
+		return nil, err
+	}
+	if len(pp) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	return pp[0], nil
+}
+
+// r_Products reads the products with all their values in the order of ids
+func (do *DbOperator) r_Products(tx pgx.Tx, ids []int) ([]*internal.Product, error) {
+	rows, err := tx.Query(context.Background(),
+		`SELECT ID_PRODUCT, NAME, ID_PARENT_CLASS, VERSION
 			FROM PRODUCTS 
-			WHERE ID_PRODUCT = $1`,
-		id).Scan(&name, &idParent); err != nil {
+			WHERE ID_PRODUCT = ANY($1)`,
+		ids)
+	if err != nil {
+		return nil, err
+	}
+	products := make(map[int]*internal.Product)
+	parents := make(map[int]int)
+	var id, idParent, version int
+	var name string
+	for rows.Next() {
+		if err = rows.Scan(&id, &name, &idParent, &version); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		products[id] = &internal.Product{
+			Id:      id,
+			Name:    name,
+			Version: version,
+			Params:  []*internal.ParamAndValues{},
+		}
+		parents[id] = idParent
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
 		return nil, err
 	}
-	class, err := do.r_Class(tx, idParent, false)
+	classIds := make([]int, 0, len(parents))
+	for _, idParent := range parents {
+		classIds = append(classIds, idParent)
+	}
+	classes, err := do.r_Classes(tx, classIds)
 	if err != nil {
 		return nil, err
 	}
-	p := &internal.Product{
-		Id:          id,
-		Name:        name,
-		ParentClass: class,
-		Params:      []*internal.ParamAndValues{},
+	for id, idParent := range parents {
+		products[id].ParentClass = classes[idParent]
 	}
-	rows, err := tx.Query(context.Background(),
-		`SELECT P.NAME, VT.NAME, EI.NAME, EI.SHORT_NAME, PPV.VALUE
+	rows, err = tx.Query(context.Background(),
+		`SELECT PPV.ID_PRODUCT, P.ID_PARAM, CP.ID_CLASS, P.NAME, VT.NAME, VT.BASE_TYPE, EI.NAME, EI.SHORT_NAME, 
+				PPV.VALUE_INTEGER, PPV.VALUE_DECIMAL::TEXT, PPV.VALUE_BOOLEAN, PPV.VALUE_DATE, 
+				COALESCE(PPV.VALUE_STRING, EV.VALUE)
 			FROM PRODUCT_PARAM_VALUES PPV JOIN CLASS_PARAMS CP ON PPV.ID_PARAM = CP.ID_CLASS_PARAM
 										JOIN PARAMS P ON P.ID_PARAM = CP.ID_PARAM
 										JOIN VALUE_TYPES VT ON P.ID_VALUE_TYPE = VT.ID_VALUE_TYPE
 										JOIN EI ON EI.ID_EI = P.ID_EI
-			WHERE PPV.ID_PRODUCT = $1`,
-		id)
+										LEFT JOIN ENUM_VALUES EV ON EV.ID_ENUM_VALUE = PPV.VALUE_ENUM
+			WHERE PPV.ID_PRODUCT = ANY($1)
+			ORDER BY PPV.ID_PRODUCT, CP.ID_CLASS_PARAM`,
+		ids)
 	if err != nil {
 		return nil, err
 	}
-	var paramName, paramValueType, paramEiName, paramEiShortName, value string
+	var idProduct, idParam, idParamOwner int
+	var paramName, paramValueType, paramBaseType, paramEiName, paramEiShortName string
 	for rows.Next() {
-		if err = rows.Scan(&paramName, &paramValueType, &paramEiName, &paramEiShortName, &value); err != nil {
+		var tv typedValue
+		if err = rows.Scan(&idProduct, &idParam, &idParamOwner, &paramName, &paramValueType, &paramBaseType, &paramEiName, &paramEiShortName,
+			&tv.Integer, &tv.Decimal, &tv.Boolean, &tv.Date, &tv.String); err != nil {
 			rows.Close()
 			return nil, err
 		}
+		p := products[idProduct]
 		p.Params = append(p.Params, &internal.ParamAndValues{
 			Param: &internal.Param{
-				Id:      0,
+				IdParamOwner: idParamOwner,
+				Id:      idParam,
 				Name:    paramName,
 				ValType: paramValueType,
+				BaseType: paramBaseType,
 				EI: &internal.EI{
 					Id:        0,
 					Name:      paramEiName,
 					ShortName: paramEiShortName,
 				},
 			},
-			Value: value,
+			Value: tv.value(),
 		})
 	}
 	rows.Close()
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
-	return p, nil
+	result := make([]*internal.Product, 0, len(ids))
+	for _, id := range ids {
+		if p, ok := products[id]; ok {
+			result = append(result, p)
+		}
+	}
+	return result, nil
 }
 
-func (do *DbOperator) r_ClassProducts(tx pgx.Tx, idClass int) ([]*internal.Product, error) {
-	var pp []*internal.Product
-	rows, err := tx.Query(context.Background(),
-		`SELECT P.ID_PRODUCT 
-			FROM PRODUCTS P JOIN CLASSES C ON P.ID_PARENT_CLASS = C.ID_CLASS
-			WHERE C.ID_CLASS = $1`,
-		idClass)
+// r_ClassProducts reads a page of the class products, besides id and name they can be sorted
+// by the value of a param with the param:<name> sort key
+func (do *DbOperator) r_ClassProducts(tx pgx.Tx, idClass int, page *Page) ([]*internal.Product, error) {
+	key, desc := page.sortKey()
+	var rows pgx.Rows
+	var err error
+	if strings.HasPrefix(key, "param:") {
+		direction := " NULLS LAST"
+		if desc {
+			direction = " DESC NULLS LAST"
+		}
+		order := " ORDER BY SV.VALUE_INTEGER" + direction + ", SV.VALUE_DECIMAL" + direction +
+			", SV.VALUE_BOOLEAN" + direction + ", SV.VALUE_DATE" + direction +
+			", SV.VALUE_STRING" + direction + ", PR.ID_PRODUCT"
+		rows, err = tx.Query(context.Background(),
+			`SELECT PR.ID_PRODUCT
+				FROM PRODUCTS PR LEFT JOIN (
+					SELECT PPV.ID_PRODUCT, PPV.VALUE_INTEGER, PPV.VALUE_DECIMAL, PPV.VALUE_BOOLEAN, PPV.VALUE_DATE,
+						COALESCE(PPV.VALUE_STRING, EV.VALUE) AS VALUE_STRING
+					FROM PRODUCT_PARAM_VALUES PPV JOIN CLASS_PARAMS CP ON PPV.ID_PARAM = CP.ID_CLASS_PARAM
+												JOIN PARAMS P ON P.ID_PARAM = CP.ID_PARAM
+												LEFT JOIN ENUM_VALUES EV ON EV.ID_ENUM_VALUE = PPV.VALUE_ENUM
+					WHERE P.NAME = $2) SV ON SV.ID_PRODUCT = PR.ID_PRODUCT
+				WHERE PR.ID_PARENT_CLASS = $1`+order+page.limit(),
+			idClass, strings.TrimPrefix(key, "param:"))
+	} else {
+		var order string
+		if order, err = page.orderBy(map[string]string{
+			"id":   "ID_PRODUCT",
+			"name": "NAME",
+		}, "id"); err != nil {
+			return nil, err
+		}
+		rows, err = tx.Query(context.Background(),
+			`SELECT ID_PRODUCT 
+				FROM PRODUCTS
+				WHERE ID_PARENT_CLASS = $1`+order+page.limit(),
+			idClass)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -680,21 +1752,61 @@ func (do *DbOperator) r_ClassProducts(tx pgx.Tx, idClass int) ([]*internal.Produ
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
-	for _, id := range ids {
-		p, err := do.r_Product(tx, id)
-		if err != nil {
-			return nil, err
-		}
-		pp = append(pp, p)
-	}
-	return pp, nil
+	return do.r_Products(tx, ids)
 }
 
+// u_Product replaces the name, class and all the values of the product keeping its id
 func (do *DbOperator) u_Product(tx pgx.Tx, p *internal.Product) (err error) {
-	if err = do.d_Product(tx, p.Id); err != nil {
+	current, err := do.r_Product(tx, p.Id)
+	if err != nil {
+		return err
+	}
+	if p.ParentClass == nil {
+		p.ParentClass = current.ParentClass
+	}
+	terminal, err := do.r_ProductClass(tx, p.ParentClass)
+	if err != nil {
+		return err
+	}
+	p.ParentClass = terminal
+	class, err := do.r_Class(tx, terminal.Id, true)
+	if err != nil {
+		return err
+	}
+	if _, err = tx.Exec(context.Background(),
+		`UPDATE PRODUCTS
+			SET NAME = $2, ID_PARENT_CLASS = $3
+			WHERE ID_PRODUCT = $1`,
+		p.Id, p.Name, class.Id); err != nil {
+		return
+	}
+	if _, err = tx.Exec(context.Background(),
+		`DELETE FROM PRODUCT_PARAM_VALUES
+			WHERE ID_PRODUCT = $1`,
+		p.Id); err != nil {
 		return
 	}
-	return do.c_Product(tx, p)
+	return do.c_ProductParams(tx, p.Id, p, class)
+}
+
+// u_ProductPatch changes the name if it is set and only the listed values of the product
+func (do *DbOperator) u_ProductPatch(tx pgx.Tx, id int, patch *internal.Product) error {
+	current, err := do.r_Product(tx, id)
+	if err != nil {
+		return err
+	}
+	if patch.Name != "" && patch.Name != current.Name {
+		if _, err := tx.Exec(context.Background(),
+			`UPDATE PRODUCTS
+				SET NAME = $2
+				WHERE ID_PRODUCT = $1`,
+			id, patch.Name); err != nil {
+			return err
+		}
+		current.Name = patch.Name
+	}
+	current.Params = patch.Params
+	return do.u_ProductParams(tx, current)
 }
 
 func (do *DbOperator) d_Product(tx pgx.Tx, id int) (err error) {
@@ -716,7 +1828,7 @@ func (do *DbOperator) d_Product(tx pgx.Tx, id int) (err error) {
 func (do *DbOperator) CreateProducts(pp []*internal.Product) (err error) {
 	f := func(tx pgx.Tx) error {
 		for _, p := range pp {
-			if err := do.c_Product(tx, p); err != nil {
+			if _, err := do.c_Product(tx, p); err != nil {
 				return err
 			}
 		}
@@ -725,34 +1837,88 @@ func (do *DbOperator) CreateProducts(pp []*internal.Product) (err error) {
 	return do.cs.WrapIntoTransaction(context.Background(), f)
 }
 
-func (do *DbOperator) ReadProduct(id int) (*internal.Product, error) {
+// ReadProduct reads the product, if unit is set the values are converted to it where possible,
+// otherwise to the display units of the class params
+func (do *DbOperator) ReadProduct(id int, unit string) (*internal.Product, error) {
 	var p *internal.Product
 	f := func(tx pgx.Tx) error {
 		pr, err := do.r_Product(tx, id)
 		if err != nil {
 			return err
 		}
+		if err := do.convertProducts(tx, []*internal.Product{pr}, unit); err != nil {
+			return err
+		}
 		p = pr
 		return nil
 	}
 	return p, do.cs.WrapIntoTransaction(context.Background(), f)
 }
 
-func (do *DbOperator) ReadClassProducts(id int) ([]*internal.Product, error) {
+func (do *DbOperator) ReadClassProducts(id int, unit string, page *Page) ([]*internal.Product, int, error) {
 	var pp []*internal.Product
+	var total int
 	f := func(tx pgx.Tx) error {
-		products, err := do.r_ClassProducts(tx, id)
+		products, err := do.r_ClassProducts(tx, id, page)
 		if err != nil {
 			return err
 		}
+		if err := tx.QueryRow(context.Background(),
+			`SELECT COUNT(*)
+				FROM PRODUCTS
+				WHERE ID_PARENT_CLASS = $1`,
+			id).Scan(&total); err != nil {
+			return err
+		}
+		if err := do.convertProducts(tx, products, unit); err != nil {
+			return err
+		}
 		pp = products
 		return nil
 	}
-	return pp, do.cs.WrapIntoTransaction(context.Background(), f)
+	return pp, total, do.cs.WrapIntoTransaction(context.Background(), f)
+}
+
+// ReadClassProductsBatches reads the products of the class by batches of the size and passes every batch
+// to send. The batches come from one snapshot, so products created or deleted meanwhile are neither
+// skipped nor repeated
+func (do *DbOperator) ReadClassProductsBatches(id int, unit, sort string, size int,
+	send func([]*internal.Product) error) error {
+	f := func(tx pgx.Tx) error {
+		if _, err := tx.Exec(context.Background(),
+			`SET TRANSACTION ISOLATION LEVEL REPEATABLE READ READ ONLY`); err != nil {
+			return err
+		}
+		page := &Page{Limit: size, Sort: sort}
+		for {
+			products, err := do.r_ClassProducts(tx, id, page)
+			if err != nil {
+				return err
+			}
+			if len(products) == 0 {
+				return nil
+			}
+			if err := do.convertProducts(tx, products, unit); err != nil {
+				return err
+			}
+			if err := send(products); err != nil {
+				return err
+			}
+			if len(products) < size {
+				return nil
+			}
+			page.Offset += len(products)
+		}
+	}
+	return do.cs.WrapIntoTransaction(context.Background(), f)
 }
 
-func (do *DbOperator) UpdateProduct(p *internal.Product) error {
+// UpdateProduct replaces the product if the precondition holds for its version
+func (do *DbOperator) UpdateProduct(p *internal.Product, pc *Precondition) error {
 	f := func(tx pgx.Tx) error {
+		if err := do.u_ProductVersion(tx, p.Id, pc); err != nil {
+			return err
+		}
 		if err := do.u_Product(tx, p); err != nil {
 			return err
 		}
@@ -761,8 +1927,21 @@ func (do *DbOperator) UpdateProduct(p *internal.Product) error {
 	return do.cs.WrapIntoTransaction(context.Background(), f)
 }
 
-func (do *DbOperator) DeleteProduct(id int) error {
+func (do *DbOperator) PatchProduct(id int, patch *internal.Product, pc *Precondition) error {
 	f := func(tx pgx.Tx) error {
+		if err := do.u_ProductVersion(tx, id, pc); err != nil {
+			return err
+		}
+		return do.u_ProductPatch(tx, id, patch)
+	}
+	return do.cs.WrapIntoTransaction(context.Background(), f)
+}
+
+func (do *DbOperator) DeleteProduct(id int, pc *Precondition) error {
+	f := func(tx pgx.Tx) error {
+		if err := do.u_ProductVersion(tx, id, pc); err != nil {
+			return err
+		}
 		if err := do.d_Product(tx, id); err != nil {
 			return err
 		}
@@ -773,43 +1952,131 @@ func (do *DbOperator) DeleteProduct(id int) error {
 
 // PRODUCT PARAMS
 
-func (do *DbOperator) c_ProductParams(tx pgx.Tx, p *internal.Product) (err error) {
-	idProduct, err := do.r_ProductId(tx, p.Name)
-	if err != nil {
-		return err
+func (do *DbOperator) d_ProductParamValue(tx pgx.Tx, idProduct int, p *internal.Param) error {
+	_, err := tx.Exec(context.Background(),
+		`DELETE FROM PRODUCT_PARAM_VALUES
+			WHERE ID_PRODUCT = $1 AND ID_PARAM IN (
+				SELECT ID_CLASS_PARAM
+				FROM CLASS_PARAMS
+				WHERE ID_CLASS = $2 AND ID_PARAM = $3)`,
+		idProduct, p.IdParamOwner, p.Id)
+	return err
+}
+
+// c_ProductParams checks the values of the product against the effective params of its class
+// and writes them with the defaults of the params the product has no value for
+func (do *DbOperator) c_ProductParams(tx pgx.Tx, idProduct int, p *internal.Product, class *internal.Class) (err error) {
+	classParams := make(map[string]*internal.Param)
+	for _, p := range class.Params {
+		classParams[p.Name] = p
 	}
-	classId, err := do.r_ClassId(tx, p.ParentClass.Name)
-	if err != nil {
-		return err
+	var checked []*checkedValue
+	given := make(map[string]bool)
+	vErr := &ValidationError{}
+	for _, pnv := range p.Params {
+		searchedP, ok := classParams[pnv.Param.Name]
+		if !ok {
+			vErr.add(p.Name, pnv.Param.Name, errors.New("couldn't find param"))
+			continue
+		}
+		tv, err := do.checkValue(tx, searchedP, pnv.Value, pnv.Unit)
+		if err != nil {
+			vErr.add(p.Name, searchedP.Name, err)
+			continue
+		}
+		checked = append(checked, &checkedValue{param: searchedP, value: tv})
+		given[searchedP.Name] = true
+	}
+	for _, cp := range class.Params {
+		if given[cp.Name] {
+			continue
+		}
+		if cp.Default != nil {
+			tv, err := do.checkValue(tx, cp, cp.Default, "")
+			if err != nil {
+				vErr.add(p.Name, cp.Name, fmt.Errorf("invalid default: %w", err))
+				continue
+			}
+			checked = append(checked, &checkedValue{param: cp, value: tv})
+			continue
+		}
+		if cp.Required {
+			vErr.add(p.Name, cp.Name, errors.New("required param is missing"))
+		}
+	}
+	if len(vErr.Fields) != 0 {
+		return vErr
+	}
+	return do.c_ProductParamValues(tx, idProduct, checked)
+}
+
+// c_ProductParamValues writes the checked values, replacing the ones the product already has
+func (do *DbOperator) c_ProductParamValues(tx pgx.Tx, idProduct int, checked []*checkedValue) (err error) {
+	for _, cv := range checked {
+		tv := cv.value
+		// the class param is found by the insert itself, so a value costs one query
+		tag, err := tx.Exec(context.Background(),
+			`INSERT INTO PRODUCT_PARAM_VALUES(ID_PRODUCT, ID_PARAM, 
+					VALUE_INTEGER, VALUE_DECIMAL, VALUE_BOOLEAN, VALUE_DATE, VALUE_STRING, VALUE_ENUM)
+				SELECT $1, CP.ID_CLASS_PARAM, $4, $5, $6, $7, $8, $9
+				FROM CLASS_PARAMS CP
+				WHERE CP.ID_CLASS = $2 AND CP.ID_PARAM = $3
+				ON CONFLICT (ID_PRODUCT, ID_PARAM) DO UPDATE
+				SET VALUE_INTEGER = EXCLUDED.VALUE_INTEGER, VALUE_DECIMAL = EXCLUDED.VALUE_DECIMAL,
+					VALUE_BOOLEAN = EXCLUDED.VALUE_BOOLEAN, VALUE_DATE = EXCLUDED.VALUE_DATE,
+					VALUE_STRING = EXCLUDED.VALUE_STRING, VALUE_ENUM = EXCLUDED.VALUE_ENUM`,
+			idProduct, cv.param.IdParamOwner, cv.param.Id, tv.Integer, tv.Decimal, tv.Boolean, tv.Date, tv.String, tv.Enum)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("couldn't find param %s of class %d", cv.param.Name, cv.param.IdParamOwner)
+		}
 	}
-	class, err := do.r_Class(tx, classId, true)
+	return nil
+}
+
+// u_ProductParams changes only the given values, a null value removes the value of the param
+func (do *DbOperator) u_ProductParams(tx pgx.Tx, p *internal.Product) error {
+	class, err := do.r_Class(tx, p.ParentClass.Id, true)
 	if err != nil {
 		return err
 	}
 	classParams := make(map[string]*internal.Param)
-	for _, p := range class.Params {
-		classParams[p.Name] = p
+	for _, cp := range class.Params {
+		classParams[cp.Name] = cp
 	}
+	var checked []*checkedValue
+	var removed []*internal.Param
+	vErr := &ValidationError{}
 	for _, pnv := range p.Params {
-		var idParam int
-		searchedP, ok := classParams[pnv.Param.Name]
+		cp, ok := classParams[pnv.Param.Name]
 		if !ok {
-			return errors.New("couldn't find param")
+			vErr.add(p.Name, pnv.Param.Name, errors.New("couldn't find param"))
+			continue
 		}
-		if err = tx.QueryRow(context.Background(),
-			`SELECT ID_CLASS_PARAM 
-				FROM CLASS_PARAMS
-				WHERE ID_CLASS = $1 AND ID_PARAM = $2`,
-			searchedP.IdParamOwner, searchedP.Id).Scan(&idParam); err != nil {
-			return
+		if pnv.Value == nil {
+			if cp.Required {
+				vErr.add(p.Name, cp.Name, errors.New("required param can't be removed"))
+				continue
+			}
+			removed = append(removed, cp)
+			continue
 		}
-		_, err = tx.Exec(context.Background(),
-			`INSERT INTO PRODUCT_PARAM_VALUES(ID_PRODUCT, ID_PARAM, VALUE)
-				VALUES ($1,$2,$3)`,
-			idProduct, idParam, pnv.Value)
+		tv, err := do.checkValue(tx, cp, pnv.Value, pnv.Unit)
 		if err != nil {
-			return
+			vErr.add(p.Name, cp.Name, err)
+			continue
 		}
+		checked = append(checked, &checkedValue{param: cp, value: tv})
 	}
-	return nil
+	if len(vErr.Fields) != 0 {
+		return vErr
+	}
+	for _, cp := range removed {
+		if err := do.d_ProductParamValue(tx, p.Id, cp); err != nil {
+			return err
+		}
+	}
+	return do.c_ProductParamValues(tx, p.Id, checked)
 }
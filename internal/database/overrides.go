@@ -0,0 +1,311 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"hseSQL/internal"
+	"math/big"
+)
+
+// CLASS_PARAM_OVERRIDES
+
+func (do *DbOperator) c_ParamOverrides(tx pgx.Tx, idClass int, overrides []*internal.ParamOverride) error {
+	if len(overrides) == 0 {
+		return nil
+	}
+	class, err := do.r_Class(tx, idClass, true)
+	if err != nil {
+		return err
+	}
+	params := make(map[string]*internal.Param)
+	for _, p := range class.Params {
+		params[p.Name] = p
+	}
+	for _, o := range overrides {
+		inherited, ok := params[o.Name]
+		if !ok {
+			return newInvalidErr("couldn't find inherited param %s", o.Name)
+		}
+		if inherited.IdParamOwner == idClass {
+			return newInvalidErr("param %s is defined by the class itself and can't be overridden", o.Name)
+		}
+		if err := checkOverride(inherited, o); err != nil {
+			return newInvalidErr("invalid override of param %s: %w", o.Name, err)
+		}
+		effective := *inherited
+		applyOverride(&effective, o)
+		var defaultValue sql.NullString
+		if o.Default != nil {
+			if _, err := do.checkValue(tx, &effective, o.Default, ""); err != nil {
+				return newInvalidErr("invalid default of param %s: %w", o.Name, err)
+			}
+			if defaultValue, err = encodeDefault(o.Default); err != nil {
+				return err
+			}
+		} else if effective.Default != nil && o.Constraints != nil {
+			if _, err := do.checkValue(tx, &effective, effective.Default, ""); err != nil {
+				return newInvalidErr("inherited default of param %s doesn't fit the override: %w", o.Name, err)
+			}
+		}
+		var idDisplayEi sql.NullInt32
+		if o.DisplayEI != nil {
+			display, err := do.r_Unit(tx, o.DisplayEI.Name)
+			if err != nil {
+				return err
+			}
+			own, err := do.r_Unit(tx, inherited.EI.Name)
+			if err != nil {
+				return err
+			}
+			if _, err := convertUnits(new(big.Rat), own, display); err != nil {
+				return newInvalidErr("invalid display unit of param %s: %w", o.Name, err)
+			}
+			idDisplayEi = sql.NullInt32{Int32: int32(display.Id), Valid: true}
+		}
+		var idClassParam int
+		if err := tx.QueryRow(context.Background(),
+			`SELECT ID_CLASS_PARAM
+				FROM CLASS_PARAMS
+				WHERE ID_CLASS = $1 AND ID_PARAM = $2`,
+			inherited.IdParamOwner, inherited.Id).Scan(&idClassParam); err != nil {
+			return err
+		}
+		c := o.Constraints
+		if c == nil {
+			c = &internal.ParamConstraints{}
+		}
+		_, err := tx.Exec(context.Background(),
+			`INSERT INTO CLASS_PARAM_OVERRIDES(ID_CLASS, ID_CLASS_PARAM, MIN_VALUE, MAX_VALUE, STEP, PRECISION, MAX_LENGTH,
+					REQUIRED, DEFAULT_VALUE, ID_DISPLAY_EI)
+				VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)`,
+			idClass, idClassParam, c.Min, c.Max, c.Step, c.Precision, c.MaxLength,
+			o.Required, defaultValue, idDisplayEi)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// u_ParamOverrides replaces the overrides the class has of the set params and deletes the removed ones,
+// then makes sure the overrides of the subclasses still narrow the changed definitions
+func (do *DbOperator) u_ParamOverrides(tx pgx.Tx, idClass int, set []*internal.ParamOverride, remove []string) error {
+	if len(set) == 0 && len(remove) == 0 {
+		return nil
+	}
+	for _, name := range remove {
+		deleted, err := do.d_ParamOverride(tx, idClass, name)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			return newInvalidErr("class has no override of param %s", name)
+		}
+	}
+	// the old override is deleted first, so the new one is checked against the inherited definition
+	for _, o := range set {
+		if _, err := do.d_ParamOverride(tx, idClass, o.Name); err != nil {
+			return err
+		}
+	}
+	if err := do.c_ParamOverrides(tx, idClass, set); err != nil {
+		return err
+	}
+	return do.checkSubtreeOverrides(tx, idClass)
+}
+
+// d_ParamOverride deletes the override the class itself has of the param, reports if there was one
+func (do *DbOperator) d_ParamOverride(tx pgx.Tx, idClass int, name string) (bool, error) {
+	tag, err := tx.Exec(context.Background(),
+		`DELETE FROM CLASS_PARAM_OVERRIDES CPO
+			USING CLASS_PARAMS CP, PARAMS P
+			WHERE CPO.ID_CLASS = $1 AND CP.ID_CLASS_PARAM = CPO.ID_CLASS_PARAM
+				AND P.ID_PARAM = CP.ID_PARAM AND P.NAME = $2`,
+		idClass, name)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() != 0, nil
+}
+
+// checkSubtreeOverrides checks the overrides of the descendants of the class against
+// the definitions they inherit from their parents
+func (do *DbOperator) checkSubtreeOverrides(tx pgx.Tx, idClass int) error {
+	subtree, err := do.r_SubtreeIds(tx, idClass)
+	if err != nil {
+		return err
+	}
+	stored, err := do.r_ClassOverrides(tx)
+	if err != nil {
+		return err
+	}
+	for _, id := range subtree {
+		if id == idClass || len(stored[id]) == 0 {
+			continue
+		}
+		class, err := do.r_Class(tx, id, false)
+		if err != nil {
+			return err
+		}
+		idParent, err := do.r_ParentClass(tx, class.Name)
+		if err != nil {
+			return err
+		}
+		parent, err := do.r_Class(tx, int(idParent.Int32), true)
+		if err != nil {
+			return err
+		}
+		params := make(map[string]*internal.Param)
+		for _, p := range parent.Params {
+			params[p.Name] = p
+		}
+		for _, o := range stored[id] {
+			inherited, ok := params[o.Name]
+			if !ok {
+				continue
+			}
+			if err := checkOverride(inherited, o); err != nil {
+				return newConflictErr("override of param %s in class %s: %w", o.Name, class.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// applyParamOverrides merges the overrides of the class and its ancestors into
+// the class params, overrides of the deeper classes win
+func (do *DbOperator) applyParamOverrides(tx pgx.Tx, c *internal.Class) error {
+	rows, err := tx.Query(context.Background(),
+		`WITH RECURSIVE ANCESTORS AS (
+				SELECT ID_CLASS, ID_PARENT_CLASS, 0 AS DEPTH
+				FROM CLASSES
+				WHERE ID_CLASS = $1 UNION
+				SELECT C.ID_CLASS, C.ID_PARENT_CLASS, A.DEPTH + 1
+				FROM CLASSES C INNER JOIN ANCESTORS A ON A.ID_PARENT_CLASS = C.ID_CLASS)
+			SELECT CP.ID_PARAM, CPO.MIN_VALUE::FLOAT8, CPO.MAX_VALUE::FLOAT8, CPO.STEP::FLOAT8, CPO.PRECISION, CPO.MAX_LENGTH,
+				CPO.REQUIRED, CPO.DEFAULT_VALUE::TEXT, EI.NAME, EI.SHORT_NAME
+			FROM CLASS_PARAM_OVERRIDES CPO JOIN ANCESTORS A ON A.ID_CLASS = CPO.ID_CLASS
+							JOIN CLASS_PARAMS CP ON CP.ID_CLASS_PARAM = CPO.ID_CLASS_PARAM
+							LEFT JOIN EI ON EI.ID_EI = CPO.ID_DISPLAY_EI
+			ORDER BY A.DEPTH DESC`,
+		c.Id)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	params := make(map[int]*internal.Param)
+	for _, p := range c.Params {
+		params[p.Id] = p
+	}
+	for rows.Next() {
+		var idParam int
+		var defaultValue, eiName, eiShortName sql.NullString
+		o := &internal.ParamOverride{Constraints: &internal.ParamConstraints{}}
+		if err := rows.Scan(&idParam, &o.Constraints.Min, &o.Constraints.Max, &o.Constraints.Step,
+			&o.Constraints.Precision, &o.Constraints.MaxLength, &o.Required, &defaultValue,
+			&eiName, &eiShortName); err != nil {
+			return err
+		}
+		if o.Default, err = decodeDefault(defaultValue); err != nil {
+			return err
+		}
+		if eiName.Valid {
+			o.DisplayEI = &internal.EI{
+				Name:      eiName.String,
+				ShortName: eiShortName.String,
+			}
+		}
+		if p, ok := params[idParam]; ok {
+			applyOverride(p, o)
+		}
+	}
+	return rows.Err()
+}
+
+// checkOverride makes sure the override only narrows the inherited definition
+func checkOverride(inherited *internal.Param, o *internal.ParamOverride) error {
+	if o.Required != nil && !*o.Required && inherited.Required {
+		return fmt.Errorf("inherited param is required")
+	}
+	if o.Constraints == nil {
+		return nil
+	}
+	// the bounds the override doesn't set are inherited, so they are checked together
+	merged := *inherited
+	applyOverride(&merged, o)
+	if err := validateConstraints(&merged); err != nil {
+		return err
+	}
+	ic, oc := inherited.Constraints, o.Constraints
+	if ic == nil {
+		return nil
+	}
+	if ic.Min != nil && oc.Min != nil && *oc.Min < *ic.Min {
+		return fmt.Errorf("min %v is less than the inherited min %v", *oc.Min, *ic.Min)
+	}
+	if ic.Max != nil && oc.Max != nil && *oc.Max > *ic.Max {
+		return fmt.Errorf("max %v is greater than the inherited max %v", *oc.Max, *ic.Max)
+	}
+	if ic.MaxLength != nil && oc.MaxLength != nil && *oc.MaxLength > *ic.MaxLength {
+		return fmt.Errorf("max length %d is greater than the inherited max length %d", *oc.MaxLength, *ic.MaxLength)
+	}
+	if ic.Precision != nil && oc.Precision != nil && *oc.Precision > *ic.Precision {
+		return fmt.Errorf("precision %d is greater than the inherited precision %d", *oc.Precision, *ic.Precision)
+	}
+	// the values on the narrowed grid have to be on the inherited one,
+	// the grids start at min or at zero
+	if ic.Step != nil {
+		mc := merged.Constraints
+		step := ratOf(*ic.Step)
+		if !isMultiple(ratOf(*mc.Step), step) {
+			return fmt.Errorf("step %v is not a multiple of the inherited step %v", *mc.Step, *ic.Step)
+		}
+		if !isMultiple(new(big.Rat).Sub(stepBase(mc), stepBase(ic)), step) {
+			return fmt.Errorf("min %v is not on the inherited step %v", *mc.Min, *ic.Step)
+		}
+	}
+	return nil
+}
+
+func stepBase(c *internal.ParamConstraints) *big.Rat {
+	if c.Min == nil {
+		return new(big.Rat)
+	}
+	return ratOf(*c.Min)
+}
+
+func applyOverride(p *internal.Param, o *internal.ParamOverride) {
+	if o.Constraints != nil {
+		merged := internal.ParamConstraints{}
+		if p.Constraints != nil {
+			merged = *p.Constraints
+		}
+		if o.Constraints.Min != nil {
+			merged.Min = o.Constraints.Min
+		}
+		if o.Constraints.Max != nil {
+			merged.Max = o.Constraints.Max
+		}
+		if o.Constraints.Step != nil {
+			merged.Step = o.Constraints.Step
+		}
+		if o.Constraints.Precision != nil {
+			merged.Precision = o.Constraints.Precision
+		}
+		if o.Constraints.MaxLength != nil {
+			merged.MaxLength = o.Constraints.MaxLength
+		}
+		p.Constraints = &merged
+	}
+	if o.Required != nil {
+		p.Required = *o.Required
+	}
+	if o.Default != nil {
+		p.Default = o.Default
+	}
+	if o.DisplayEI != nil {
+		p.DisplayEI = o.DisplayEI
+	}
+}
@@ -0,0 +1,38 @@
+package database
+
+import (
+	"hseSQL/internal"
+	"testing"
+)
+
+func TestCheckOverride(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	n := func(v int) *int { return &v }
+	inherited := &internal.Param{
+		Name: "weight",
+		Constraints: &internal.ParamConstraints{
+			Min: f(0.1), Max: f(10), Step: f(0.1), Precision: n(2),
+		},
+	}
+	tests := []struct {
+		name string
+		c    *internal.ParamConstraints
+		ok   bool
+	}{
+		{"narrower range", &internal.ParamConstraints{Min: f(0.3), Max: f(5)}, true},
+		{"multiple step", &internal.ParamConstraints{Step: f(0.3)}, true},
+		{"smaller precision", &internal.ParamConstraints{Precision: n(1)}, true},
+		{"lower min", &internal.ParamConstraints{Min: f(0)}, false},
+		{"higher max", &internal.ParamConstraints{Max: f(11)}, false},
+		{"wider precision", &internal.ParamConstraints{Precision: n(3)}, false},
+		{"finer step", &internal.ParamConstraints{Step: f(0.05)}, false},
+		{"step off the inherited one", &internal.ParamConstraints{Step: f(0.25)}, false},
+		{"min off the inherited step", &internal.ParamConstraints{Min: f(0.15)}, false},
+	}
+	for _, tt := range tests {
+		err := checkOverride(inherited, &internal.ParamOverride{Name: "weight", Constraints: tt.c})
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: got %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Page selects a part of a sorted list, a zero limit means the rest of the list
+type Page struct {
+	Limit  int
+	Offset int
+	// Sort is a sort key prefixed with - for the descending order
+	Sort string
+}
+
+// sortKey splits the sort into the key and the direction
+func (p *Page) sortKey() (key string, desc bool) {
+	if p == nil {
+		return "", false
+	}
+	if strings.HasPrefix(p.Sort, "-") {
+		return p.Sort[1:], true
+	}
+	return p.Sort, false
+}
+
+// orderBy builds the ORDER BY clause from the allowed sort keys, the first key column
+// is also used to make the order stable
+func (p *Page) orderBy(columns map[string]string, defaultKey string) (string, error) {
+	key, desc := p.sortKey()
+	if key == "" {
+		key = defaultKey
+	}
+	column, ok := columns[key]
+	if !ok {
+		return "", newInvalidErr("can't sort by %s", key)
+	}
+	clause := " ORDER BY " + column
+	if desc {
+		clause += " DESC"
+	}
+	if key != defaultKey {
+		clause += ", " + columns[defaultKey]
+	}
+	return clause, nil
+}
+
+// limit builds the LIMIT and OFFSET clause
+func (p *Page) limit() string {
+	if p == nil {
+		return ""
+	}
+	var clause string
+	if p.Limit > 0 {
+		clause += fmt.Sprintf(" LIMIT %d", p.Limit)
+	}
+	if p.Offset > 0 {
+		clause += fmt.Sprintf(" OFFSET %d", p.Offset)
+	}
+	return clause
+}
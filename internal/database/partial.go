@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"github.com/jackc/pgx/v4"
+	"hseSQL/internal"
+)
+
+// ItemResult is the outcome of one item of a batch created in the partial mode,
+// either the id of the created item or the error it was rejected with
+type ItemResult struct {
+	Id  int
+	Err error
+}
+
+// createEach creates every item in its own transaction, so a rejected item
+// doesn't roll back the others. The results keep the order of the items
+func (do *DbOperator) createEach(n int, create func(tx pgx.Tx, i int) (int, error)) []*ItemResult {
+	res := make([]*ItemResult, 0, n)
+	for i := 0; i < n; i++ {
+		r := &ItemResult{}
+		r.Err = do.cs.WrapIntoTransaction(context.Background(), func(tx pgx.Tx) (err error) {
+			r.Id, err = create(tx, i)
+			return
+		})
+		if r.Err != nil {
+			r.Id = 0
+		}
+		res = append(res, r)
+	}
+	return res
+}
+
+// CreateEIsPartially creates the EIs one by one, the result of an existing EI is its id
+func (do *DbOperator) CreateEIsPartially(eis []*internal.EI) []*ItemResult {
+	return do.createEach(len(eis), func(tx pgx.Tx, i int) (int, error) {
+		return do.cr_EI(tx, eis[i])
+	})
+}
+
+// CreateClassesPartially creates the classes one by one, a class is created together with its children
+func (do *DbOperator) CreateClassesPartially(cc []*internal.Class) []*ItemResult {
+	return do.createEach(len(cc), func(tx pgx.Tx, i int) (int, error) {
+		return do.c_Class(tx, cc[i], sql.NullInt32{})
+	})
+}
+
+// CreateProductsPartially creates the products one by one
+func (do *DbOperator) CreateProductsPartially(pp []*internal.Product) []*ItemResult {
+	return do.createEach(len(pp), func(tx pgx.Tx, i int) (int, error) {
+		return do.c_Product(tx, pp[i])
+	})
+}
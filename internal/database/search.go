@@ -0,0 +1,399 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"hseSQL/internal"
+	"strings"
+	"unicode"
+)
+
+// predicate operators
+const (
+	OpEq  = "eq"
+	OpNe  = "ne"
+	OpGt  = "gt"
+	OpGte = "gte"
+	OpLt  = "lt"
+	OpLte = "lte"
+	OpIn  = "in"
+)
+
+var comparisons = map[string]string{
+	OpEq:  "=",
+	OpNe:  "<>",
+	OpGt:  ">",
+	OpGte: ">=",
+	OpLt:  "<",
+	OpLte: "<=",
+}
+
+// Predicate filters products by the value of a param, only the in operator takes several values
+type Predicate struct {
+	Param  string
+	Op     string
+	Values []string
+}
+
+// valueColumn returns the PRODUCT_VALUES column the values of the base type are kept in
+func valueColumn(baseType string) string {
+	switch baseType {
+	case internal.BaseTypeInteger:
+		return "VALUE_INTEGER"
+	case internal.BaseTypeDecimal:
+		return "VALUE_DECIMAL"
+	case internal.BaseTypeBoolean:
+		return "VALUE_BOOLEAN"
+	case internal.BaseTypeDate:
+		return "VALUE_DATE"
+	}
+	return "VALUE_STRING"
+}
+
+// sqlArg returns the valid field of the value as a query argument
+func (tv typedValue) sqlArg() interface{} {
+	switch {
+	case tv.Integer.Valid:
+		return tv.Integer
+	case tv.Decimal.Valid:
+		return tv.Decimal
+	case tv.Boolean.Valid:
+		return tv.Boolean
+	case tv.Date.Valid:
+		return tv.Date
+	}
+	return tv.String
+}
+
+// matchedProducts builds the MATCHED query with the products of the class subtree
+// that satisfy all the predicates
+func (do *DbOperator) matchedProducts(tx pgx.Tx, idClass int, preds []*Predicate) (string, []interface{}, error) {
+	args := []interface{}{idClass}
+	var conditions []string
+	for _, pred := range preds {
+		pp, err := do.r_Params(tx, pred.Param)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(pp) == 0 {
+			return "", nil, newInvalidErr("couldn't find param %s", pred.Param)
+		}
+		param := pp[0]
+		var operands []string
+		for _, v := range pred.Values {
+			tv, err := parseValue(param.BaseType, v)
+			if err != nil {
+				return "", nil, newInvalidErr("invalid value of param %s: %w", param.Name, err)
+			}
+			args = append(args, tv.sqlArg())
+			operands = append(operands, fmt.Sprintf("$%d", len(args)))
+		}
+		column := "V." + valueColumn(param.BaseType)
+		var condition string
+		switch {
+		case pred.Op == OpIn && len(operands) != 0:
+			condition = column + " IN (" + strings.Join(operands, ", ") + ")"
+		case comparisons[pred.Op] != "" && len(operands) == 1:
+			condition = column + " " + comparisons[pred.Op] + " " + operands[0]
+		default:
+			return "", nil, newInvalidErr("invalid filter %s on param %s", pred.Op, param.Name)
+		}
+		args = append(args, param.Id)
+		conditions = append(conditions, fmt.Sprintf(
+			`EXISTS (
+				SELECT 1
+				FROM PRODUCT_VALUES V
+				WHERE V.ID_PRODUCT = PR.ID_PRODUCT AND V.ID_PARAM = $%d AND %s)`,
+			len(args), condition))
+	}
+	query := `WITH RECURSIVE SUBCLASSES AS (
+			SELECT ID_CLASS, NAME, ID_PARENT_CLASS
+			FROM CLASSES
+			WHERE ID_CLASS = $1 UNION
+			SELECT C.ID_CLASS, C.NAME, C.ID_PARENT_CLASS
+			FROM CLASSES C INNER JOIN SUBCLASSES S ON S.ID_CLASS = C.ID_PARENT_CLASS),
+		MATCHED AS (
+			SELECT PR.ID_PRODUCT, PR.NAME
+			FROM PRODUCTS PR
+			WHERE PR.ID_PARENT_CLASS IN (SELECT ID_CLASS FROM SUBCLASSES)`
+	for _, c := range conditions {
+		query += " AND " + c
+	}
+	query += ")"
+	return query, args, nil
+}
+
+// SearchProducts finds the products of the class subtree that match all the predicates, returns the
+// requested page of them, their total count and the facets of the params. Without facet params the
+// facets are counted for all enum and boolean params
+func (do *DbOperator) SearchProducts(idClass int, preds []*Predicate, facetParams []string, page *Page) (
+	[]*internal.Product, int, []*internal.Facet, error) {
+	var pp []*internal.Product
+	var total int
+	var facets []*internal.Facet
+	f := func(tx pgx.Tx) error {
+		matched, args, err := do.matchedProducts(tx, idClass, preds)
+		if err != nil {
+			return err
+		}
+		order, err := page.orderBy(map[string]string{
+			"id":   "ID_PRODUCT",
+			"name": "NAME",
+		}, "id")
+		if err != nil {
+			return err
+		}
+		rows, err := tx.Query(context.Background(),
+			matched+` SELECT ID_PRODUCT FROM MATCHED`+order+page.limit(),
+			args...)
+		if err != nil {
+			return err
+		}
+		var ids []int
+		var id int
+		for rows.Next() {
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			ids = append(ids, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if pp, err = do.r_Products(tx, ids); err != nil {
+			return err
+		}
+		if err := tx.QueryRow(context.Background(),
+			matched+` SELECT COUNT(*) FROM MATCHED`,
+			args...).Scan(&total); err != nil {
+			return err
+		}
+		facets, err = do.r_Facets(tx, matched, args, facetParams)
+		return err
+	}
+	return pp, total, facets, do.cs.WrapIntoTransaction(context.Background(), f)
+}
+
+func (do *DbOperator) r_Facets(tx pgx.Tx, matched string, args []interface{}, params []string) ([]*internal.Facet, error) {
+	filter := `V.BASE_TYPE IN ('enum', 'boolean')`
+	if len(params) != 0 {
+		args = append(args, params)
+		filter = fmt.Sprintf("V.PARAM_NAME = ANY($%d)", len(args))
+	}
+	rows, err := tx.Query(context.Background(),
+		matched+` SELECT V.PARAM_NAME,
+				COALESCE(V.VALUE_STRING, V.VALUE_INTEGER::TEXT, V.VALUE_DECIMAL::TEXT,
+					V.VALUE_BOOLEAN::TEXT, V.VALUE_DATE::TEXT) AS FACET_VALUE,
+				COUNT(*) AS PRODUCTS_COUNT
+			FROM PRODUCT_VALUES V
+			WHERE V.ID_PRODUCT IN (SELECT ID_PRODUCT FROM MATCHED) AND `+filter+`
+			GROUP BY V.PARAM_NAME, FACET_VALUE
+			ORDER BY V.PARAM_NAME, PRODUCTS_COUNT DESC, FACET_VALUE`,
+		args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := []*internal.Facet{}
+	var last *internal.Facet
+	var param, value string
+	var count int
+	for rows.Next() {
+		if err := rows.Scan(&param, &value, &count); err != nil {
+			return nil, err
+		}
+		if last == nil || last.Param != param {
+			last = &internal.Facet{Param: param}
+			result = append(result, last)
+		}
+		last.Values = append(last.Values, &internal.FacetValue{
+			Value: value,
+			Count: count,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Search looks for the text in class names, product names, param names and string and enum
+// param values using full text search and trigram similarity, so a typo or a different case still matches.
+// Hits are ranked by the better of the two scores
+func (do *DbOperator) Search(text string, limit int) ([]*internal.SearchHit, error) {
+	var res []*internal.SearchHit
+	f := func(tx pgx.Tx) error {
+		hits, err := do.r_SearchHits(tx, text, limit)
+		if err != nil {
+			return err
+		}
+		res = hits
+		return nil
+	}
+	return res, do.cs.WrapIntoTransaction(context.Background(), f)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// r_SearchHits matches the columns of the tables rather than the PRODUCT_VALUES view,
+// so the conditions use the full text and the trigram indexes
+func (do *DbOperator) r_SearchHits(tx pgx.Tx, text string, limit int) ([]*internal.SearchHit, error) {
+	pattern := "%" + likeEscaper.Replace(text) + "%"
+	rows, err := tx.Query(context.Background(),
+		`WITH Q AS (
+				SELECT PLAINTO_TSQUERY('simple', $1) AS TSQ),
+			HITS AS (
+				SELECT 'class' AS KIND, C.ID_CLASS AS ID, C.NAME AS NAME, '' AS PARAM_NAME, C.NAME AS TEXT
+				FROM CLASSES C, Q
+				WHERE TO_TSVECTOR('simple', C.NAME) @@ Q.TSQ OR C.NAME % $1 OR C.NAME ILIKE $3
+				UNION ALL
+				SELECT 'product', PR.ID_PRODUCT, PR.NAME, '', PR.NAME
+				FROM PRODUCTS PR, Q
+				WHERE TO_TSVECTOR('simple', PR.NAME) @@ Q.TSQ OR PR.NAME % $1 OR PR.NAME ILIKE $3
+				UNION ALL
+				SELECT 'param', P.ID_PARAM, P.NAME, '', P.NAME
+				FROM PARAMS P, Q
+				WHERE TO_TSVECTOR('simple', P.NAME) @@ Q.TSQ OR P.NAME % $1 OR P.NAME ILIKE $3
+				UNION ALL
+				SELECT 'value', PR.ID_PRODUCT, PR.NAME, P.NAME, PPV.VALUE_STRING
+				FROM PRODUCT_PARAM_VALUES PPV JOIN PRODUCTS PR ON PPV.ID_PRODUCT = PR.ID_PRODUCT
+										JOIN CLASS_PARAMS CP ON PPV.ID_PARAM = CP.ID_CLASS_PARAM
+										JOIN PARAMS P ON P.ID_PARAM = CP.ID_PARAM, Q
+				WHERE TO_TSVECTOR('simple', PPV.VALUE_STRING) @@ Q.TSQ
+					OR PPV.VALUE_STRING % $1 OR PPV.VALUE_STRING ILIKE $3
+				UNION ALL
+				SELECT 'value', PR.ID_PRODUCT, PR.NAME, P.NAME, EV.VALUE
+				FROM ENUM_VALUES EV JOIN PRODUCT_PARAM_VALUES PPV ON PPV.VALUE_ENUM = EV.ID_ENUM_VALUE
+										JOIN PRODUCTS PR ON PPV.ID_PRODUCT = PR.ID_PRODUCT
+										JOIN CLASS_PARAMS CP ON PPV.ID_PARAM = CP.ID_CLASS_PARAM
+										JOIN PARAMS P ON P.ID_PARAM = CP.ID_PARAM, Q
+				WHERE TO_TSVECTOR('simple', EV.VALUE) @@ Q.TSQ OR EV.VALUE % $1 OR EV.VALUE ILIKE $3)
+			SELECT H.KIND, H.ID, H.NAME, H.PARAM_NAME, H.TEXT,
+				GREATEST(TS_RANK(TO_TSVECTOR('simple', H.TEXT), Q.TSQ), SIMILARITY(H.TEXT, $1))::FLOAT8 AS RANK
+			FROM HITS H, Q
+			ORDER BY RANK DESC, H.KIND, H.ID
+			LIMIT $2`,
+		text, limit, pattern)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := []*internal.SearchHit{}
+	for rows.Next() {
+		h := &internal.SearchHit{}
+		if err := rows.Scan(&h.Kind, &h.Id, &h.Name, &h.Param, &h.Text, &h.Rank); err != nil {
+			return nil, err
+		}
+		h.Highlights = highlights(h.Text, text)
+		result = append(result, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// similarityThreshold is the default pg_trgm threshold of the % operator
+const similarityThreshold = 0.3
+
+// highlights returns the ranges of the text that match the search: the occurrences of the whole search
+// as ILIKE finds them, and the words equal to, containing or similar to a word of the search, so the
+// hits found only by trigram similarity are marked too. The offsets count characters, not bytes
+func highlights(text, search string) []*internal.TextRange {
+	tr, sr := lowerRunes(text), lowerRunes(search)
+	marked := make([]bool, len(tr))
+	mark := func(start, end int) {
+		for i := start; i < end; i++ {
+			marked[i] = true
+		}
+	}
+	for i := 0; len(sr) != 0 && i+len(sr) <= len(tr); i++ {
+		if string(tr[i:i+len(sr)]) == string(sr) {
+			mark(i, i+len(sr))
+		}
+	}
+	words := splitWords(sr)
+	for _, w := range splitWords(tr) {
+		word := string(tr[w[0]:w[1]])
+		for _, sw := range words {
+			s := string(sr[sw[0]:sw[1]])
+			if i := strings.Index(word, s); i >= 0 {
+				start := w[0] + len([]rune(word[:i]))
+				mark(start, start+sw[1]-sw[0])
+			} else if similarity(word, s) >= similarityThreshold {
+				mark(w[0], w[1])
+			}
+		}
+	}
+	result := []*internal.TextRange{}
+	for i := 0; i < len(marked); i++ {
+		if !marked[i] {
+			continue
+		}
+		r := &internal.TextRange{Start: i}
+		for i < len(marked) && marked[i] {
+			i++
+		}
+		r.End = i
+		result = append(result, r)
+	}
+	return result
+}
+
+// lowerRunes lowers the runes one by one, so the offsets in the result are the ones of the text
+func lowerRunes(s string) []rune {
+	rr := []rune(s)
+	for i, r := range rr {
+		rr[i] = unicode.ToLower(r)
+	}
+	return rr
+}
+
+// splitWords returns the start and end offsets of the words made of letters and digits
+func splitWords(rr []rune) [][2]int {
+	var words [][2]int
+	start := -1
+	for i, r := range rr {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			words = append(words, [2]int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, [2]int{start, len(rr)})
+	}
+	return words
+}
+
+// similarity counts the shared trigrams of two words the way pg_trgm does,
+// padding each word with two spaces in front and one behind
+func similarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	common := 0
+	for t := range ta {
+		if tb[t] {
+			common++
+		}
+	}
+	all := len(ta) + len(tb) - common
+	if all == 0 {
+		return 0
+	}
+	return float64(common) / float64(all)
+}
+
+func trigrams(word string) map[string]bool {
+	rr := []rune("  " + word + " ")
+	result := make(map[string]bool)
+	for i := 0; i+3 <= len(rr); i++ {
+		result[string(rr[i:i+3])] = true
+	}
+	return result
+}
@@ -0,0 +1,31 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestHighlights(t *testing.T) {
+	tests := []struct {
+		text, search, want string
+	}{
+		{"Power cable", "cable", "6-11"},
+		{"Power Cable", "CABLE", "6-11"},
+		{"Power cable", "cabel", "6-11"},
+		{"Powercables", "cable", "5-10"},
+		{"50% off", "50%", "0-3"},
+		{"Кабель силовой", "кабель", "0-6"},
+		{"<script>alert(1)</script>", "alert", "8-13"},
+		{"Power cable", "laptop", ""},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, r := range highlights(tt.text, tt.search) {
+			got = append(got, fmt.Sprintf("%d-%d", r.Start, r.End))
+		}
+		if strings.Join(got, " ") != tt.want {
+			t.Errorf("highlights(%q, %q) = %v, want %s", tt.text, tt.search, got, tt.want)
+		}
+	}
+}
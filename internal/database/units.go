@@ -0,0 +1,283 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"hseSQL/internal"
+	"math/big"
+)
+
+// DIMENSIONS
+
+func (do *DbOperator) CreateDimensions(names []string) ([]int, error) {
+	var ids []int
+	f := func(tx pgx.Tx) error {
+		for _, name := range names {
+			id, err := do.cr_Dimension(tx, name)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+		return nil
+	}
+	return ids, do.cs.WrapIntoTransaction(context.Background(), f)
+}
+
+func (do *DbOperator) ReadDimensions() ([]*internal.Dimension, error) {
+	var res []*internal.Dimension
+	f := func(tx pgx.Tx) error {
+		dd, err := do.r_Dimensions(tx)
+		if err != nil {
+			return err
+		}
+		res = dd
+		return nil
+	}
+	return res, do.cs.WrapIntoTransaction(context.Background(), f)
+}
+
+func (do *DbOperator) cr_Dimension(tx pgx.Tx, name string) (id int, err error) {
+	err = tx.QueryRow(context.Background(),
+		`SELECT ID_DIMENSION
+			FROM DIMENSIONS
+			WHERE NAME = $1`,
+		name).Scan(&id)
+	if err == pgx.ErrNoRows {
+		err = tx.QueryRow(context.Background(),
+			`INSERT INTO DIMENSIONS(NAME)
+				VALUES($1)
+				RETURNING ID_DIMENSION`,
+			name).Scan(&id)
+	}
+	return
+}
+
+func (do *DbOperator) r_Dimensions(tx pgx.Tx) ([]*internal.Dimension, error) {
+	rows, err := tx.Query(context.Background(),
+		`SELECT D.ID_DIMENSION, D.NAME, EI.ID_EI, EI.NAME, EI.SHORT_NAME, EI.FACTOR::FLOAT8, EI.OFFSET_VALUE::FLOAT8
+			FROM DIMENSIONS D LEFT JOIN EI ON EI.ID_DIMENSION = D.ID_DIMENSION
+			ORDER BY D.ID_DIMENSION, EI.ID_EI`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var result []*internal.Dimension
+	var last *internal.Dimension
+	var idDimension int
+	var dimensionName string
+	var idEi sql.NullInt32
+	var eiName, eiShortName sql.NullString
+	var factor, offset sql.NullFloat64
+	for rows.Next() {
+		if err := rows.Scan(&idDimension, &dimensionName, &idEi, &eiName, &eiShortName, &factor, &offset); err != nil {
+			return nil, err
+		}
+		if last == nil || last.Id != idDimension {
+			last = &internal.Dimension{
+				Id:    idDimension,
+				Name:  dimensionName,
+				Units: []*internal.EI{},
+			}
+			result = append(result, last)
+		}
+		if !idEi.Valid {
+			continue
+		}
+		last.Units = append(last.Units, &internal.EI{
+			Id:        int(idEi.Int32),
+			Name:      eiName.String,
+			ShortName: eiShortName.String,
+			Dimension: dimensionName,
+			Factor:    factor.Float64,
+			Offset:    offset.Float64,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// UNIT CONVERSION
+
+// r_Unit finds an EI by its name or, if there is no such name, by its short name
+func (do *DbOperator) r_Unit(tx pgx.Tx, unit string) (*internal.EI, error) {
+	ei := &internal.EI{}
+	var dimension sql.NullString
+	err := tx.QueryRow(context.Background(),
+		`SELECT EI.ID_EI, EI.NAME, EI.SHORT_NAME, D.NAME, EI.FACTOR::FLOAT8, EI.OFFSET_VALUE::FLOAT8
+			FROM EI LEFT JOIN DIMENSIONS D ON EI.ID_DIMENSION = D.ID_DIMENSION
+			WHERE EI.NAME = $1 OR EI.SHORT_NAME = $1
+			ORDER BY EI.NAME = $1 DESC
+			LIMIT 1`,
+		unit).Scan(&ei.Id, &ei.Name, &ei.ShortName, &dimension, &ei.Factor, &ei.Offset)
+	if err == pgx.ErrNoRows {
+		return nil, newInvalidErr("unknown unit %s", unit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	ei.Dimension = dimension.String
+	return ei, nil
+}
+
+// convertUnits converts the value without rounding, the factors
+// and offsets are taken by their shortest decimal form
+func convertUnits(v *big.Rat, from, to *internal.EI) (*big.Rat, error) {
+	if from.Dimension == "" || from.Dimension != to.Dimension {
+		return nil, fmt.Errorf("can't convert %s to %s", from.Name, to.Name)
+	}
+	var factors [4]*big.Rat
+	for i, f := range []float64{from.Factor, from.Offset, to.Offset, to.Factor} {
+		r, err := parseNumber(f)
+		if err != nil {
+			return nil, err
+		}
+		factors[i] = r
+	}
+	if factors[3].Sign() == 0 {
+		return nil, fmt.Errorf("%s has no factor", to.Name)
+	}
+	res := new(big.Rat).Mul(v, factors[0])
+	res.Add(res, factors[1])
+	res.Sub(res, factors[2])
+	return res.Quo(res, factors[3]), nil
+}
+
+// convertToParamUnit converts a decimal value given in unit to the EI of the param,
+// integers are parsed as decimals to be converted
+func (do *DbOperator) convertToParamUnit(tx pgx.Tx, tv typedValue, unit, paramEi string) (typedValue, error) {
+	from, err := do.r_Unit(tx, unit)
+	if err != nil {
+		return tv, err
+	}
+	to, err := do.r_Unit(tx, paramEi)
+	if err != nil {
+		return tv, err
+	}
+	if !tv.Decimal.Valid {
+		return tv, fmt.Errorf("only numbers can be given in a unit")
+	}
+	r, err := parseNumber(json.Number(tv.Decimal.String))
+	if err != nil {
+		return tv, err
+	}
+	v, err := convertUnits(r, from, to)
+	if err != nil {
+		return tv, err
+	}
+	tv.Decimal.String = formatDecimal(v)
+	return tv, nil
+}
+
+// convertProducts converts numeric values of the products to unit where
+// the EI of the param has the same dimension, other values are left as they are.
+// Without unit the values are converted to the display units of the class params
+func (do *DbOperator) convertProducts(tx pgx.Tx, pp []*internal.Product, unit string) error {
+	units := make(map[string]*internal.EI)
+	if unit != "" {
+		to, err := do.r_Unit(tx, unit)
+		if err != nil {
+			return err
+		}
+		for _, p := range pp {
+			for _, pnv := range p.Params {
+				if err := do.convertValue(tx, pnv, to, units); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if len(pp) == 0 {
+		return nil
+	}
+	// the params of the classes are only needed when some override has a display unit
+	var displayed bool
+	if err := tx.QueryRow(context.Background(),
+		`SELECT EXISTS (
+				SELECT 1
+				FROM CLASS_PARAM_OVERRIDES
+				WHERE ID_DISPLAY_EI IS NOT NULL)`).Scan(&displayed); err != nil {
+		return err
+	}
+	if !displayed {
+		return nil
+	}
+	ids := make([]int, 0, len(pp))
+	for _, p := range pp {
+		ids = append(ids, p.ParentClass.Id)
+	}
+	classParams, err := do.r_ClassesParams(tx, ids)
+	if err != nil {
+		return err
+	}
+	classes := make(map[int]map[string]*internal.Param)
+	for id, cpp := range classParams {
+		params := make(map[string]*internal.Param)
+		for _, cp := range cpp {
+			params[cp.Name] = cp
+		}
+		classes[id] = params
+	}
+	for _, p := range pp {
+		params := classes[p.ParentClass.Id]
+		for _, pnv := range p.Params {
+			cp, ok := params[pnv.Param.Name]
+			if !ok || cp.DisplayEI == nil {
+				continue
+			}
+			to, ok := units[cp.DisplayEI.Name]
+			if !ok {
+				var err error
+				if to, err = do.r_Unit(tx, cp.DisplayEI.Name); err != nil {
+					return err
+				}
+				units[to.Name] = to
+			}
+			if err := do.convertValue(tx, pnv, to, units); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// convertValue converts a numeric product value to the unit if the dimensions match
+func (do *DbOperator) convertValue(tx pgx.Tx, pnv *internal.ParamAndValues, to *internal.EI, units map[string]*internal.EI) error {
+	if pnv.Param.BaseType != internal.BaseTypeInteger && pnv.Param.BaseType != internal.BaseTypeDecimal {
+		return nil
+	}
+	from, ok := units[pnv.Param.EI.Name]
+	if !ok {
+		var err error
+		if from, err = do.r_Unit(tx, pnv.Param.EI.Name); err != nil {
+			return err
+		}
+		units[from.Name] = from
+	}
+	if from.Dimension == "" || from.Dimension != to.Dimension {
+		return nil
+	}
+	var v *big.Rat
+	switch value := pnv.Value.(type) {
+	case int64, float64, json.Number:
+		var err error
+		if v, err = parseNumber(value); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+	converted, err := convertUnits(v, from, to)
+	if err != nil {
+		return err
+	}
+	pnv.Value = json.Number(formatDecimal(converted))
+	pnv.Param.EI = to
+	return nil
+}
@@ -0,0 +1,427 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"hseSQL/internal"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	dateLayout     = "2006-01-02"
+	maxStringValue = 300
+	// maxDecimalPlaces bounds the decimals that are kept, a unit conversion may give a fraction that never ends
+	maxDecimalPlaces = 30
+)
+
+var numberPattern = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d{1,3})?$`)
+
+// typedValue is a product parameter value split into the column it is stored in,
+// exactly one of the fields is valid. Decimals are kept as their exact text, so NUMERIC
+// doesn't lose precision on the way through float64. Enum values are parsed into String and
+// are replaced with the id of the enum value before they are written
+type typedValue struct {
+	Integer sql.NullInt64
+	Decimal sql.NullString
+	Boolean sql.NullBool
+	Date    sql.NullTime
+	String  sql.NullString
+	Enum    sql.NullInt32
+}
+
+func isBaseType(name string) bool {
+	switch name {
+	case internal.BaseTypeInteger, internal.BaseTypeDecimal, internal.BaseTypeBoolean,
+		internal.BaseTypeDate, internal.BaseTypeString, internal.BaseTypeEnum:
+		return true
+	}
+	return false
+}
+
+// parseValue checks that the raw value (as it came from json) fits the base type
+func parseValue(baseType string, raw interface{}) (tv typedValue, err error) {
+	if raw == nil {
+		return tv, errors.New("value is missing")
+	}
+	switch baseType {
+	case internal.BaseTypeInteger:
+		var r *big.Rat
+		if r, err = parseNumber(raw); err != nil {
+			return
+		}
+		if !r.IsInt() {
+			return tv, fmt.Errorf("%v is not an integer", raw)
+		}
+		if !r.Num().IsInt64() {
+			return tv, fmt.Errorf("%v is out of the integer range", raw)
+		}
+		tv.Integer = sql.NullInt64{Int64: r.Num().Int64(), Valid: true}
+	case internal.BaseTypeDecimal:
+		var r *big.Rat
+		if r, err = parseNumber(raw); err != nil {
+			return
+		}
+		s := formatDecimal(r)
+		if exact, _ := new(big.Rat).SetString(s); exact.Cmp(r) != 0 {
+			return tv, fmt.Errorf("%v has more than %d decimal places", raw, maxDecimalPlaces)
+		}
+		tv.Decimal = sql.NullString{String: s, Valid: true}
+	case internal.BaseTypeBoolean:
+		switch v := raw.(type) {
+		case bool:
+			tv.Boolean = sql.NullBool{Bool: v, Valid: true}
+		case string:
+			b, perr := strconv.ParseBool(strings.TrimSpace(v))
+			if perr != nil {
+				return tv, fmt.Errorf("%q is not a boolean", v)
+			}
+			tv.Boolean = sql.NullBool{Bool: b, Valid: true}
+		default:
+			return tv, fmt.Errorf("%v is not a boolean", raw)
+		}
+	case internal.BaseTypeDate:
+		s, ok := raw.(string)
+		if !ok {
+			return tv, fmt.Errorf("%v is not a date", raw)
+		}
+		d, perr := time.Parse(dateLayout, strings.TrimSpace(s))
+		if perr != nil {
+			return tv, fmt.Errorf("%q is not a date in %s format", s, dateLayout)
+		}
+		tv.Date = sql.NullTime{Time: d, Valid: true}
+	case internal.BaseTypeString:
+		s, ok := raw.(string)
+		if !ok {
+			return tv, fmt.Errorf("%v is not a string", raw)
+		}
+		if len([]rune(s)) > maxStringValue {
+			return tv, fmt.Errorf("string is longer than %d characters", maxStringValue)
+		}
+		tv.String = sql.NullString{String: s, Valid: true}
+	case internal.BaseTypeEnum:
+		s, ok := raw.(string)
+		if !ok {
+			return tv, fmt.Errorf("%v is not an enum value", raw)
+		}
+		tv.String = sql.NullString{String: s, Valid: true}
+	default:
+		return tv, fmt.Errorf("unknown base type %q", baseType)
+	}
+	return tv, nil
+}
+
+// parseNumber reads the raw number exactly, a float64 is taken by its shortest decimal form
+func parseNumber(raw interface{}) (*big.Rat, error) {
+	var s string
+	switch v := raw.(type) {
+	case int:
+		return new(big.Rat).SetInt64(int64(v)), nil
+	case int64:
+		return new(big.Rat).SetInt64(v), nil
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	case json.Number:
+		s = string(v)
+	case string:
+		s = strings.TrimSpace(v)
+	default:
+		return nil, fmt.Errorf("%v is not a number", raw)
+	}
+	if !numberPattern.MatchString(s) {
+		return nil, fmt.Errorf("%q is not a number", s)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a number", s)
+	}
+	return r, nil
+}
+
+// formatDecimal writes the number with as many decimal places as it has
+// but no more than maxDecimalPlaces, without trailing zeros
+func formatDecimal(r *big.Rat) string {
+	ten := big.NewRat(10, 1)
+	places := 0
+	for x := new(big.Rat).Set(r); !x.IsInt() && places < maxDecimalPlaces; places++ {
+		x.Mul(x, ten)
+	}
+	s := r.FloatString(places)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	if s == "-0" {
+		s = "0"
+	}
+	return s
+}
+
+// decimalPlaces counts the places of a decimal written by formatDecimal
+func decimalPlaces(s string) int {
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		return len(s) - i - 1
+	}
+	return 0
+}
+
+// value returns the stored value in the form it is encoded to json
+func (tv typedValue) value() interface{} {
+	switch {
+	case tv.Integer.Valid:
+		return tv.Integer.Int64
+	case tv.Decimal.Valid:
+		// NUMERIC keeps the scale it was written with, the trailing zeros are dropped
+		if r, err := parseNumber(json.Number(tv.Decimal.String)); err == nil {
+			return json.Number(formatDecimal(r))
+		}
+		return json.Number(tv.Decimal.String)
+	case tv.Boolean.Valid:
+		return tv.Boolean.Bool
+	case tv.Date.Valid:
+		return tv.Date.Time.Format(dateLayout)
+	case tv.String.Valid:
+		return tv.String.String
+	}
+	return nil
+}
+
+// encodeDefault keeps the default value as json, so it is read back with the same type
+func encodeDefault(v interface{}) (sql.NullString, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+func decodeDefault(s sql.NullString) (interface{}, error) {
+	if !s.Valid {
+		return nil, nil
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(s.String), &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// FieldError describes why a single param value of a product was rejected
+type FieldError struct {
+	Product string `json:"product,omitempty"`
+	Param   string `json:"param"`
+	Message string `json:"message"`
+}
+
+// ValidationError reports every rejected param value of a product at once
+type ValidationError struct {
+	Fields []*FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", f.Param, f.Message))
+	}
+	return "invalid param values: " + strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) add(product, param string, err error) {
+	e.Fields = append(e.Fields, &FieldError{
+		Product: product,
+		Param:   param,
+		Message: err.Error(),
+	})
+}
+
+var errMoveConflicts = errors.New("products conflict with the params of the new parent")
+
+// MoveReport lists the product values that don't fit the params inherited after a class move.
+// A forced move drops them, unless a required param would be left without a value,
+// then the class isn't moved and only those conflicts are listed
+type MoveReport struct {
+	Moved     bool          `json:"moved"`
+	Conflicts []*FieldError `json:"conflicts"`
+}
+
+type productConflicts struct {
+	Product   *internal.Product
+	Conflicts []*FieldError
+	// Orphans are the values of params the class doesn't have anymore
+	Orphans []*internal.ParamAndValues
+	// Invalid are the values that don't fit the params of the class, dropping them
+	// leaves the product valid unless the param is required
+	Invalid []*internal.ParamAndValues
+	// Unresolved are the conflicts dropping the values can't fix: a required param is missing
+	// or its value is invalid
+	Unresolved []*FieldError
+}
+
+// checkStoredProduct checks the values a product already has against the current params of its class
+func checkStoredProduct(p *internal.Product, class *internal.Class) *productConflicts {
+	type paramKey struct {
+		owner, id int
+	}
+	params := make(map[paramKey]*internal.Param)
+	for _, cp := range class.Params {
+		params[paramKey{cp.IdParamOwner, cp.Id}] = cp
+	}
+	res := &productConflicts{Product: p}
+	conflict := func(param, message string) {
+		res.Conflicts = append(res.Conflicts, &FieldError{
+			Product: p.Name,
+			Param:   param,
+			Message: message,
+		})
+	}
+	given := make(map[string]bool)
+	for _, pnv := range p.Params {
+		cp, ok := params[paramKey{pnv.Param.IdParamOwner, pnv.Param.Id}]
+		if !ok {
+			res.Orphans = append(res.Orphans, pnv)
+			conflict(pnv.Param.Name, "param is not inherited by the class anymore")
+			continue
+		}
+		given[cp.Name] = true
+		tv, err := parseValue(cp.BaseType, pnv.Value)
+		if err == nil {
+			err = checkConstraints(tv, cp.Constraints)
+		}
+		if err != nil {
+			conflict(cp.Name, err.Error())
+			res.Invalid = append(res.Invalid, pnv)
+			if cp.Required && cp.Default == nil {
+				res.Unresolved = append(res.Unresolved, res.Conflicts[len(res.Conflicts)-1])
+			}
+		}
+	}
+	for _, cp := range class.Params {
+		if !given[cp.Name] && cp.Required && cp.Default == nil {
+			conflict(cp.Name, "required param is missing")
+			res.Unresolved = append(res.Unresolved, res.Conflicts[len(res.Conflicts)-1])
+		}
+	}
+	return res
+}
+
+type checkedValue struct {
+	param *internal.Param
+	value typedValue
+}
+
+// checkValue parses the raw value of the param, converts it from the unit it was given in
+// to the EI of the param and checks it against the param constraints
+func (do *DbOperator) checkValue(tx pgx.Tx, p *internal.Param, raw interface{}, unit string) (typedValue, error) {
+	converted := unit != "" && unit != p.EI.Name && unit != p.EI.ShortName
+	baseType := p.BaseType
+	if converted && baseType == internal.BaseTypeInteger {
+		// the value has to be whole in the EI of the param, not in the unit it was given in
+		baseType = internal.BaseTypeDecimal
+	}
+	tv, err := parseValue(baseType, raw)
+	if err != nil {
+		return tv, err
+	}
+	if converted {
+		if tv, err = do.convertToParamUnit(tx, tv, unit, p.EI.Name); err != nil {
+			return tv, err
+		}
+		if baseType != p.BaseType {
+			if tv, err = parseValue(p.BaseType, json.Number(tv.Decimal.String)); err != nil {
+				return tv, fmt.Errorf("%v %s is not a whole number of %s", raw, unit, p.EI.Name)
+			}
+		}
+	}
+	if err := checkConstraints(tv, p.Constraints); err != nil {
+		return tv, err
+	}
+	if p.BaseType == internal.BaseTypeEnum {
+		idEnum, err := do.r_EnumValueId(tx, p.ValType, tv.String.String)
+		if err != nil {
+			return tv, err
+		}
+		tv.String = sql.NullString{}
+		tv.Enum = sql.NullInt32{Int32: int32(idEnum), Valid: true}
+	}
+	return tv, nil
+}
+
+// validateConstraints checks that the constraints of the param make sense for its value type
+func validateConstraints(p *internal.Param) error {
+	c := p.Constraints
+	if c == nil {
+		return nil
+	}
+	if c.Min != nil && c.Max != nil && *c.Min > *c.Max {
+		return newInvalidErr("param %s: min is greater than max", p.Name)
+	}
+	if c.Step != nil && *c.Step <= 0 {
+		return newInvalidErr("param %s: step must be positive", p.Name)
+	}
+	if c.Precision != nil && *c.Precision < 0 {
+		return newInvalidErr("param %s: precision can't be negative", p.Name)
+	}
+	if c.MaxLength != nil && *c.MaxLength <= 0 {
+		return newInvalidErr("param %s: max length must be positive", p.Name)
+	}
+	return nil
+}
+
+// checkConstraints applies the numeric constraints to numbers and the length one to strings
+func checkConstraints(tv typedValue, c *internal.ParamConstraints) error {
+	if c == nil {
+		return nil
+	}
+	if tv.String.Valid && c.MaxLength != nil && len([]rune(tv.String.String)) > *c.MaxLength {
+		return fmt.Errorf("value is longer than %d characters", *c.MaxLength)
+	}
+	// the value and the constraints are compared as exact fractions, as 0.3 is not a multiple of 0.1 in floats
+	var text string
+	switch {
+	case tv.Integer.Valid:
+		text = strconv.FormatInt(tv.Integer.Int64, 10)
+	case tv.Decimal.Valid:
+		text = tv.Decimal.String
+	default:
+		return nil
+	}
+	v, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return fmt.Errorf("invalid number %s", text)
+	}
+	if c.Min != nil && v.Cmp(ratOf(*c.Min)) < 0 {
+		return fmt.Errorf("%s is less than the minimum %v", text, *c.Min)
+	}
+	if c.Max != nil && v.Cmp(ratOf(*c.Max)) > 0 {
+		return fmt.Errorf("%s is greater than the maximum %v", text, *c.Max)
+	}
+	if c.Step != nil {
+		base := new(big.Rat)
+		if c.Min != nil {
+			base = ratOf(*c.Min)
+		}
+		if !isMultiple(new(big.Rat).Sub(v, base), ratOf(*c.Step)) {
+			return fmt.Errorf("%s is not a multiple of the step %v", text, *c.Step)
+		}
+	}
+	if c.Precision != nil && tv.Decimal.Valid && decimalPlaces(tv.Decimal.String) > *c.Precision {
+		return fmt.Errorf("%s has more than %d decimal places", tv.Decimal.String, *c.Precision)
+	}
+	return nil
+}
+
+// ratOf returns the decimal a constraint is written as rather than its binary approximation
+func ratOf(f float64) *big.Rat {
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	return r
+}
+
+// isMultiple reports if a is a whole multiple of b
+func isMultiple(a, b *big.Rat) bool {
+	return new(big.Rat).Quo(a, b).IsInt()
+}
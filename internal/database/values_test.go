@@ -0,0 +1,40 @@
+package database
+
+import (
+	"database/sql"
+	"hseSQL/internal"
+	"testing"
+)
+
+func TestCheckConstraints(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	decimal := func(s string) typedValue {
+		return typedValue{Decimal: sql.NullString{String: s, Valid: true}}
+	}
+	integer := func(i int64) typedValue {
+		return typedValue{Integer: sql.NullInt64{Int64: i, Valid: true}}
+	}
+	tests := []struct {
+		tv typedValue
+		c  *internal.ParamConstraints
+		ok bool
+	}{
+		{decimal("0.3"), &internal.ParamConstraints{Step: f(0.1)}, true},
+		{decimal("0.7"), &internal.ParamConstraints{Min: f(0.1), Step: f(0.2)}, true},
+		{decimal("0.35"), &internal.ParamConstraints{Step: f(0.1)}, false},
+		{decimal("0.30000000000000001"), &internal.ParamConstraints{Step: f(0.1)}, false},
+		{decimal("0.1"), &internal.ParamConstraints{Min: f(0.1), Max: f(0.3)}, true},
+		{decimal("0.3"), &internal.ParamConstraints{Min: f(0.1), Max: f(0.3)}, true},
+		{decimal("0.0999999999999999999"), &internal.ParamConstraints{Min: f(0.1)}, false},
+		{decimal("0.3000000000000000001"), &internal.ParamConstraints{Max: f(0.3)}, false},
+		{integer(9007199254740993), &internal.ParamConstraints{Max: f(9007199254740992)}, false},
+		{integer(10), &internal.ParamConstraints{Min: f(1), Step: f(3)}, true},
+		{integer(11), &internal.ParamConstraints{Min: f(1), Step: f(3)}, false},
+	}
+	for _, tt := range tests {
+		err := checkConstraints(tt.tv, tt.c)
+		if (err == nil) != tt.ok {
+			t.Errorf("checkConstraints(%+v, %+v): got %v", tt.tv, *tt.c, err)
+		}
+	}
+}
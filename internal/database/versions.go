@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+)
+
+// Precondition lists the versions a class or a product may have for a change to apply,
+// a nil precondition always holds and an empty one never does
+type Precondition struct {
+	Versions []int
+}
+
+func (pc *Precondition) holds(version int) bool {
+	if pc == nil {
+		return true
+	}
+	for _, v := range pc.Versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// u_ClassVersion locks the class until the end of the transaction, checks its version
+// and increases it as the class is about to change
+func (do *DbOperator) u_ClassVersion(tx pgx.Tx, id int, pc *Precondition) error {
+	var version int
+	if err := tx.QueryRow(context.Background(),
+		`SELECT VERSION
+			FROM CLASSES
+			WHERE ID_CLASS = $1
+			FOR UPDATE`,
+		id).Scan(&version); err != nil {
+		return err
+	}
+	if !pc.holds(version) {
+		return ErrVersionMismatch
+	}
+	_, err := tx.Exec(context.Background(),
+		`UPDATE CLASSES
+			SET VERSION = VERSION + 1
+			WHERE ID_CLASS = $1`,
+		id)
+	return err
+}
+
+// u_ProductVersion is u_ClassVersion for a product
+func (do *DbOperator) u_ProductVersion(tx pgx.Tx, id int, pc *Precondition) error {
+	var version int
+	if err := tx.QueryRow(context.Background(),
+		`SELECT VERSION
+			FROM PRODUCTS
+			WHERE ID_PRODUCT = $1
+			FOR UPDATE`,
+		id).Scan(&version); err != nil {
+		return err
+	}
+	if !pc.holds(version) {
+		return ErrVersionMismatch
+	}
+	_, err := tx.Exec(context.Background(),
+		`UPDATE PRODUCTS
+			SET VERSION = VERSION + 1
+			WHERE ID_PRODUCT = $1`,
+		id)
+	return err
+}
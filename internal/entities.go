@@ -1,35 +1,174 @@
 package internal
 
+import "encoding/json"
+
+// base types a value type can be built on
+const (
+	BaseTypeInteger = "integer"
+	BaseTypeDecimal = "decimal"
+	BaseTypeBoolean = "boolean"
+	BaseTypeDate    = "date"
+	BaseTypeString  = "string"
+	BaseTypeEnum    = "enum"
+)
+
+// EI is a unit of measure, units of the same dimension are converted
+// through the base unit of the dimension: base = value * Factor + Offset
 type EI struct {
-	Id        int    `json:"id"`
-	Name      string `json:"name"`
-	ShortName string `json:"short_name"`
+	Id        int     `json:"id"`
+	Name      string  `json:"name"`
+	ShortName string  `json:"short_name"`
+	Dimension string  `json:"dimension,omitempty"`
+	Factor    float64 `json:"factor,omitempty"`
+	Offset    float64 `json:"offset,omitempty"`
+}
+
+type Dimension struct {
+	Id    int    `json:"id"`
+	Name  string `json:"name"`
+	Units []*EI  `json:"units"`
+}
+
+type ValueType struct {
+	Id       int          `json:"id"`
+	Name     string       `json:"name"`
+	BaseType string       `json:"base_type"`
+	Values   []*EnumValue `json:"values,omitempty"`
+}
+
+// UnmarshalJSON also accepts a bare type name, which is treated as a string type
+func (vt *ValueType) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		vt.Name = name
+		vt.BaseType = BaseTypeString
+		return nil
+	}
+	type plain ValueType
+	return json.Unmarshal(data, (*plain)(vt))
+}
+
+// EnumValue is one of the allowed values of an enum value type,
+// retired values are kept for existing products but can't be used for new ones
+type EnumValue struct {
+	Id      int    `json:"id"`
+	Value   string `json:"value"`
+	Retired bool   `json:"retired"`
+}
+
+// UnmarshalJSON also accepts a bare value
+func (ev *EnumValue) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		ev.Value = value
+		return nil
+	}
+	type plain EnumValue
+	return json.Unmarshal(data, (*plain)(ev))
+}
+
+// ParamConstraints limit the values a product can have for a class param,
+// unset fields are not checked
+type ParamConstraints struct {
+	Min       *float64 `json:"min,omitempty"`
+	Max       *float64 `json:"max,omitempty"`
+	Step      *float64 `json:"step,omitempty"`
+	Precision *int     `json:"precision,omitempty"`
+	MaxLength *int     `json:"max_length,omitempty"`
 }
 
 type Param struct {
-	IdParamOwner int    `json:"-"`
-	Id           int    `json:"id"`
-	Name         string `json:"name"`
-	ValType      string `json:"val_type"`
-	EI           *EI    `json:"ei"`
+	IdParamOwner int               `json:"-"`
+	Id           int               `json:"id"`
+	Name         string            `json:"name"`
+	ValType      string            `json:"val_type"`
+	BaseType     string            `json:"base_type,omitempty"`
+	EI           *EI               `json:"ei"`
+	Constraints  *ParamConstraints `json:"constraints,omitempty"`
+	Required     bool              `json:"required"`
+	// Default is used for products that don't have a value for the param
+	Default interface{} `json:"default,omitempty"`
+	// DisplayEI is the unit the values are shown in, set by class overrides
+	DisplayEI *EI `json:"display_ei,omitempty"`
+}
+
+// ParamOverride narrows a param inherited from an ancestor class,
+// unset fields keep the inherited definition
+type ParamOverride struct {
+	Name        string            `json:"name"`
+	Constraints *ParamConstraints `json:"constraints,omitempty"`
+	Required    *bool             `json:"required,omitempty"`
+	Default     interface{}       `json:"default,omitempty"`
+	DisplayEI   *EI               `json:"display_ei,omitempty"`
 }
 
 type ParamAndValues struct {
 	Param *Param      `json:"param"`
 	Value interface{} `json:"value"`
+	// Unit the value is given in, if it differs from the EI of the param
+	Unit string `json:"unit,omitempty"`
 }
 
 type Class struct {
-	Id       int      `json:"id"`
-	Name     string   `json:"name"`
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+	// Version grows with every change of the class, zero when it wasn't read
+	Version  int      `json:"version,omitempty"`
 	Children []*Class `json:"children"`
 	Ei       *EI      `json:"ei"`
 	Params   []*Param `json:"params"`
+	// Overrides are set when the class is created, ClassUpdate changes them later
+	Overrides []*ParamOverride `json:"overrides,omitempty"`
+}
+
+// ClassUpdate changes a class in place, unset fields are left as they are
+type ClassUpdate struct {
+	Name         string   `json:"name,omitempty"`
+	Ei           *EI      `json:"ei,omitempty"`
+	AddParams    []*Param `json:"add_params,omitempty"`
+	RemoveParams []string `json:"remove_params,omitempty"`
+	// SetOverrides adds the overrides of inherited params or replaces the ones the class has
+	SetOverrides    []*ParamOverride `json:"set_overrides,omitempty"`
+	RemoveOverrides []string         `json:"remove_overrides,omitempty"`
+	// DropValues confirms that product values of the removed params are deleted,
+	// without it params that have values can't be removed
+	DropValues bool `json:"drop_values"`
 }
 
 type Product struct {
-	Id          int               `json:"id"`
-	Name        string            `json:"name"`
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+	// Version grows with every change of the product, zero when it wasn't read
+	Version     int               `json:"version,omitempty"`
 	ParentClass *Class            `json:"parent_class"`
 	Params      []*ParamAndValues `json:"params"`
 }
+
+// Facet counts the products of a search result by the values of a param
+type Facet struct {
+	Param  string        `json:"param"`
+	Values []*FacetValue `json:"values"`
+}
+
+type FacetValue struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// SearchHit is a class, a product or a string value of a product matching a text search,
+// for value hits Id and Name are the ones of the product
+type SearchHit struct {
+	Kind       string       `json:"kind"`
+	Id         int          `json:"id"`
+	Name       string       `json:"name"`
+	Param      string       `json:"param,omitempty"`
+	Text       string       `json:"text"`
+	Highlights []*TextRange `json:"highlights"`
+	Rank       float64      `json:"rank"`
+}
+
+// TextRange is the part of a text from Start to End, both counted in characters
+type TextRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
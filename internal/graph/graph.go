@@ -0,0 +1,132 @@
+// Package graph serves the class tree and the products over GraphQL
+package graph
+
+import (
+	"encoding/json"
+	"github.com/graph-gophers/graphql-go"
+	log "github.com/sirupsen/logrus"
+	"hseSQL/internal/database"
+	"net/http"
+)
+
+const (
+	// maxDepth bounds the nesting of a query, the class tree can be walked both ways without end
+	maxDepth = 15
+	// maxProducts is the default and the largest page of products of a class
+	maxProducts = 100
+)
+
+const schema = `
+schema {
+	query: Query
+}
+
+# Value is a param value of any base type, dates are YYYY-MM-DD strings
+scalar Value
+
+type Query {
+	class(id: Int!): Class
+	# root classes of the tree
+	classes: [Class!]!
+	product(id: Int!, unit: String): Product
+	params(name: String): [Param!]!
+	eis(name: String): [EI!]!
+}
+
+type Class {
+	id: Int!
+	name: String!
+	ei: EI
+	parent: Class
+	# from the root down to the parent
+	ancestors: [Class!]!
+	children: [Class!]!
+	# own and inherited params with the overrides applied
+	params: [Param!]!
+	# sort is one of id, name with - for the descending order, limit is 100 at most
+	products(limit: Int, offset: Int, sort: String, unit: String): ProductPage!
+}
+
+type ProductPage {
+	total: Int!
+	items: [Product!]!
+}
+
+type Product {
+	id: Int!
+	name: String!
+	class: Class!
+	# values of the listed params or of all of them
+	values(params: [String!]): [ParamValue!]!
+}
+
+type ParamValue {
+	param: Param!
+	value: Value
+}
+
+type Param {
+	id: Int!
+	name: String!
+	valueType: String!
+	baseType: String
+	ei: EI
+	displayEi: EI
+	required: Boolean!
+	default: Value
+	constraints: Constraints
+}
+
+type Constraints {
+	min: Float
+	max: Float
+	step: Float
+	precision: Int
+	maxLength: Int
+}
+
+type EI {
+	id: Int!
+	name: String!
+	shortName: String!
+	dimension: String
+	factor: Float
+	offset: Float
+}
+`
+
+type Handler struct {
+	do     *database.DbOperator
+	schema *graphql.Schema
+}
+
+func NewHandler(do *database.DbOperator) *Handler {
+	return &Handler{
+		do:     do,
+		schema: graphql.MustParseSchema(schema, &Resolver{do: do}, graphql.MaxDepth(maxDepth)),
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	type request struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+	re := &request{}
+	if err := json.NewDecoder(req.Body).Decode(&re); err != nil {
+		log.Error(err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	ctx := withLoaders(req.Context(), newLoaders(h.do))
+	res := h.schema.Exec(ctx, re.Query, re.OperationName, re.Variables)
+	for _, err := range res.Errors {
+		log.Error(err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		log.Error(err)
+		return
+	}
+}
@@ -0,0 +1,100 @@
+package graph
+
+import (
+	"context"
+	"hseSQL/internal/database"
+	"sync"
+)
+
+// batch remembers the ids that were seen together, e.g. the children of a class.
+// Loading one of them loads the whole group, so a nested query takes a query per level
+// instead of a query per item
+type batch struct {
+	mu     sync.Mutex
+	groups map[int][]int
+}
+
+func newBatch() *batch {
+	return &batch{groups: make(map[int][]int)}
+}
+
+func (b *batch) add(ids []int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, id := range ids {
+		if _, ok := b.groups[id]; !ok {
+			b.groups[id] = ids
+		}
+	}
+}
+
+func (b *batch) group(id int) []int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if ids, ok := b.groups[id]; ok {
+		return ids
+	}
+	return []int{id}
+}
+
+// loader loads items by ids in groups of the batch and keeps them for the rest of the request,
+// missing items are loaded as nil
+type loader struct {
+	mu     sync.Mutex
+	batch  *batch
+	fetch  func(ids []int) (map[int]interface{}, error)
+	loaded map[int]interface{}
+}
+
+func newLoader(b *batch, fetch func(ids []int) (map[int]interface{}, error)) *loader {
+	return &loader{batch: b, fetch: fetch, loaded: make(map[int]interface{})}
+}
+
+func (l *loader) load(id int) (interface{}, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if v, ok := l.loaded[id]; ok {
+		return v, nil
+	}
+	ids := []int{id}
+	for _, other := range l.batch.group(id) {
+		if _, ok := l.loaded[other]; !ok && other != id {
+			ids = append(ids, other)
+		}
+	}
+	res, err := l.fetch(ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		l.loaded[id] = res[id]
+	}
+	return l.loaded[id], nil
+}
+
+type loadersKey struct{}
+
+// loaders live for one request, so nothing is cached between requests
+type loaders struct {
+	do        *database.DbOperator
+	classes   *batch
+	nodes     *loader
+	ancestors *loader
+	params    *loader
+	// products keeps a loader per page and unit as the classes may ask for different pages
+	mu       sync.Mutex
+	products map[productsKey]*loader
+}
+
+type productsKey struct {
+	limit, offset int
+	sort, unit    string
+}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
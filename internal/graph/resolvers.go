@@ -0,0 +1,496 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hseSQL/internal"
+	"hseSQL/internal/database"
+)
+
+func newLoaders(do *database.DbOperator) *loaders {
+	classes := newBatch()
+	return &loaders{
+		do:      do,
+		classes: classes,
+		nodes: newLoader(classes, func(ids []int) (map[int]interface{}, error) {
+			nodes, err := do.ReadClassNodes(ids)
+			if err != nil {
+				return nil, err
+			}
+			// the children of the whole level are loaded together
+			var children []int
+			res := make(map[int]interface{})
+			for id, node := range nodes {
+				res[id] = node
+				children = append(children, node.IdChildren...)
+			}
+			classes.add(children)
+			return res, nil
+		}),
+		ancestors: newLoader(classes, func(ids []int) (map[int]interface{}, error) {
+			ancestors, err := do.ReadAncestorIds(ids)
+			if err != nil {
+				return nil, err
+			}
+			seen := make(map[int]bool)
+			var all []int
+			res := make(map[int]interface{})
+			for _, id := range ids {
+				res[id] = ancestors[id]
+				for _, ancestor := range ancestors[id] {
+					if !seen[ancestor] {
+						seen[ancestor] = true
+						all = append(all, ancestor)
+					}
+				}
+			}
+			classes.add(all)
+			return res, nil
+		}),
+		params: newLoader(classes, func(ids []int) (map[int]interface{}, error) {
+			params, err := do.ReadClassesParams(ids)
+			if err != nil {
+				return nil, err
+			}
+			res := make(map[int]interface{})
+			for _, id := range ids {
+				res[id] = params[id]
+			}
+			return res, nil
+		}),
+		products: make(map[productsKey]*loader),
+	}
+}
+
+// productPage is what the products loader keeps for a class
+type productPage struct {
+	total int
+	items []*internal.Product
+}
+
+func (l *loaders) productsLoader(key productsKey) *loader {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if pl, ok := l.products[key]; ok {
+		return pl
+	}
+	page := &database.Page{Limit: key.limit, Offset: key.offset, Sort: key.sort}
+	pl := newLoader(l.classes, func(ids []int) (map[int]interface{}, error) {
+		pp, totals, err := l.do.ReadClassesProducts(ids, key.unit, page)
+		if err != nil {
+			return nil, err
+		}
+		res := make(map[int]interface{})
+		for _, id := range ids {
+			res[id] = &productPage{total: totals[id], items: pp[id]}
+		}
+		return res, nil
+	})
+	l.products[key] = pl
+	return pl
+}
+
+// classResolvers makes the resolvers of the classes loaded together
+func (l *loaders) classResolvers(ids []int) []*classResolver {
+	l.classes.add(ids)
+	res := make([]*classResolver, 0, len(ids))
+	for _, id := range ids {
+		res = append(res, &classResolver{id: id, l: l})
+	}
+	return res
+}
+
+type Resolver struct {
+	do *database.DbOperator
+}
+
+func (r *Resolver) Class(ctx context.Context, args struct{ Id int32 }) (*classResolver, error) {
+	l := loadersFrom(ctx)
+	c := l.classResolvers([]int{int(args.Id)})[0]
+	if _, err := c.node(); err != nil {
+		if errors.Is(err, errNoClass) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *Resolver) Classes(ctx context.Context) ([]*classResolver, error) {
+	ids, err := r.do.ReadRootClassIds()
+	if err != nil {
+		return nil, err
+	}
+	return loadersFrom(ctx).classResolvers(ids), nil
+}
+
+func (r *Resolver) Product(ctx context.Context, args struct {
+	Id   int32
+	Unit *string
+}) (*productResolver, error) {
+	var unit string
+	if args.Unit != nil {
+		unit = *args.Unit
+	}
+	pp, err := r.do.ReadProducts([]int{int(args.Id)}, unit)
+	if err != nil || len(pp) == 0 {
+		return nil, err
+	}
+	l := loadersFrom(ctx)
+	l.classes.add([]int{pp[0].ParentClass.Id})
+	return &productResolver{p: pp[0], l: l}, nil
+}
+
+func (r *Resolver) Params(args struct{ Name *string }) ([]*paramResolver, error) {
+	var name string
+	if args.Name != nil {
+		name = *args.Name
+	}
+	pp, err := r.do.ReadParams(name)
+	if err != nil {
+		return nil, err
+	}
+	return paramResolvers(pp), nil
+}
+
+func (r *Resolver) Eis(args struct{ Name *string }) ([]*eiResolver, error) {
+	var name string
+	if args.Name != nil {
+		name = *args.Name
+	}
+	eis, _, err := r.do.ReadEI(name, nil)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*eiResolver, 0, len(eis))
+	for _, ei := range eis {
+		res = append(res, &eiResolver{ei: ei})
+	}
+	return res, nil
+}
+
+var errNoClass = errors.New("couldn't find class")
+
+type classResolver struct {
+	id int
+	l  *loaders
+}
+
+func (c *classResolver) node() (*database.ClassNode, error) {
+	v, err := c.l.nodes.load(c.id)
+	if err != nil {
+		return nil, err
+	}
+	node, ok := v.(*database.ClassNode)
+	if !ok {
+		return nil, errNoClass
+	}
+	return node, nil
+}
+
+func (c *classResolver) Id() int32 {
+	return int32(c.id)
+}
+
+func (c *classResolver) Name() (string, error) {
+	node, err := c.node()
+	if err != nil {
+		return "", err
+	}
+	return node.Class.Name, nil
+}
+
+func (c *classResolver) Ei() (*eiResolver, error) {
+	node, err := c.node()
+	if err != nil || node.Class.Ei == nil {
+		return nil, err
+	}
+	return &eiResolver{ei: node.Class.Ei}, nil
+}
+
+func (c *classResolver) Parent() (*classResolver, error) {
+	node, err := c.node()
+	if err != nil || node.IdParent == 0 {
+		return nil, err
+	}
+	return c.l.classResolvers([]int{node.IdParent})[0], nil
+}
+
+func (c *classResolver) Ancestors() ([]*classResolver, error) {
+	v, err := c.l.ancestors.load(c.id)
+	if err != nil {
+		return nil, err
+	}
+	ids, _ := v.([]int)
+	return c.l.classResolvers(ids), nil
+}
+
+func (c *classResolver) Children() ([]*classResolver, error) {
+	node, err := c.node()
+	if err != nil {
+		return nil, err
+	}
+	return c.l.classResolvers(node.IdChildren), nil
+}
+
+func (c *classResolver) Params() ([]*paramResolver, error) {
+	v, err := c.l.params.load(c.id)
+	if err != nil {
+		return nil, err
+	}
+	pp, _ := v.([]*internal.Param)
+	return paramResolvers(pp), nil
+}
+
+func (c *classResolver) Products(args struct {
+	Limit  *int32
+	Offset *int32
+	Sort   *string
+	Unit   *string
+}) (*productPageResolver, error) {
+	key := productsKey{limit: maxProducts}
+	if args.Limit != nil {
+		key.limit = int(*args.Limit)
+	}
+	if args.Offset != nil {
+		key.offset = int(*args.Offset)
+	}
+	if args.Sort != nil {
+		key.sort = *args.Sort
+	}
+	if args.Unit != nil {
+		key.unit = *args.Unit
+	}
+	if key.limit < 0 || key.offset < 0 {
+		return nil, errors.New("limit and offset can't be negative")
+	}
+	if key.limit == 0 || key.limit > maxProducts {
+		return nil, fmt.Errorf("limit must be between 1 and %d", maxProducts)
+	}
+	v, err := c.l.productsLoader(key).load(c.id)
+	if err != nil {
+		return nil, err
+	}
+	page := v.(*productPage)
+	// the classes of the products are the ones of the page, so they are loaded already
+	res := &productPageResolver{total: page.total}
+	for _, p := range page.items {
+		res.items = append(res.items, &productResolver{p: p, l: c.l})
+	}
+	return res, nil
+}
+
+type productPageResolver struct {
+	total int
+	items []*productResolver
+}
+
+func (pr *productPageResolver) Total() int32 {
+	return int32(pr.total)
+}
+
+func (pr *productPageResolver) Items() []*productResolver {
+	return pr.items
+}
+
+type productResolver struct {
+	p *internal.Product
+	l *loaders
+}
+
+func (pr *productResolver) Id() int32 {
+	return int32(pr.p.Id)
+}
+
+func (pr *productResolver) Name() string {
+	return pr.p.Name
+}
+
+func (pr *productResolver) Class() *classResolver {
+	return &classResolver{id: pr.p.ParentClass.Id, l: pr.l}
+}
+
+func (pr *productResolver) Values(args struct{ Params *[]string }) []*paramValueResolver {
+	var wanted map[string]bool
+	if args.Params != nil {
+		wanted = make(map[string]bool)
+		for _, name := range *args.Params {
+			wanted[name] = true
+		}
+	}
+	res := []*paramValueResolver{}
+	for _, pnv := range pr.p.Params {
+		if wanted == nil || wanted[pnv.Param.Name] {
+			res = append(res, &paramValueResolver{pnv: pnv})
+		}
+	}
+	return res
+}
+
+type paramValueResolver struct {
+	pnv *internal.ParamAndValues
+}
+
+func (pv *paramValueResolver) Param() *paramResolver {
+	return &paramResolver{p: pv.pnv.Param}
+}
+
+func (pv *paramValueResolver) Value() *value {
+	return newValue(pv.pnv.Value)
+}
+
+func paramResolvers(pp []*internal.Param) []*paramResolver {
+	res := make([]*paramResolver, 0, len(pp))
+	for _, p := range pp {
+		res = append(res, &paramResolver{p: p})
+	}
+	return res
+}
+
+type paramResolver struct {
+	p *internal.Param
+}
+
+func (pr *paramResolver) Id() int32 {
+	return int32(pr.p.Id)
+}
+
+func (pr *paramResolver) Name() string {
+	return pr.p.Name
+}
+
+func (pr *paramResolver) ValueType() string {
+	return pr.p.ValType
+}
+
+func (pr *paramResolver) BaseType() *string {
+	if pr.p.BaseType == "" {
+		return nil
+	}
+	return &pr.p.BaseType
+}
+
+func (pr *paramResolver) Ei() *eiResolver {
+	if pr.p.EI == nil {
+		return nil
+	}
+	return &eiResolver{ei: pr.p.EI}
+}
+
+func (pr *paramResolver) DisplayEi() *eiResolver {
+	if pr.p.DisplayEI == nil {
+		return nil
+	}
+	return &eiResolver{ei: pr.p.DisplayEI}
+}
+
+func (pr *paramResolver) Required() bool {
+	return pr.p.Required
+}
+
+func (pr *paramResolver) Default() *value {
+	return newValue(pr.p.Default)
+}
+
+func (pr *paramResolver) Constraints() *constraintsResolver {
+	if pr.p.Constraints == nil {
+		return nil
+	}
+	return &constraintsResolver{c: pr.p.Constraints}
+}
+
+type constraintsResolver struct {
+	c *internal.ParamConstraints
+}
+
+func (cr *constraintsResolver) Min() *float64 {
+	return cr.c.Min
+}
+
+func (cr *constraintsResolver) Max() *float64 {
+	return cr.c.Max
+}
+
+func (cr *constraintsResolver) Step() *float64 {
+	return cr.c.Step
+}
+
+func (cr *constraintsResolver) Precision() *int32 {
+	return toInt32(cr.c.Precision)
+}
+
+func (cr *constraintsResolver) MaxLength() *int32 {
+	return toInt32(cr.c.MaxLength)
+}
+
+func toInt32(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	i := int32(*v)
+	return &i
+}
+
+type eiResolver struct {
+	ei *internal.EI
+}
+
+func (er *eiResolver) Id() int32 {
+	return int32(er.ei.Id)
+}
+
+func (er *eiResolver) Name() string {
+	return er.ei.Name
+}
+
+func (er *eiResolver) ShortName() string {
+	return er.ei.ShortName
+}
+
+func (er *eiResolver) Dimension() *string {
+	if er.ei.Dimension == "" {
+		return nil
+	}
+	return &er.ei.Dimension
+}
+
+func (er *eiResolver) Factor() *float64 {
+	if er.ei.Dimension == "" {
+		return nil
+	}
+	return &er.ei.Factor
+}
+
+func (er *eiResolver) Offset() *float64 {
+	if er.ei.Dimension == "" {
+		return nil
+	}
+	return &er.ei.Offset
+}
+
+// value is the Value scalar, it is written as the JSON of the param value
+type value struct {
+	v interface{}
+}
+
+func newValue(v interface{}) *value {
+	if v == nil {
+		return nil
+	}
+	return &value{v: v}
+}
+
+func (value) ImplementsGraphQLType(name string) bool {
+	return name == "Value"
+}
+
+func (v *value) UnmarshalGraphQL(input interface{}) error {
+	v.v = input
+	return nil
+}
+
+func (v value) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.v)
+}
@@ -0,0 +1,246 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Problem is a part of the request that doesn't match the document,
+// At is the name of the parameter or the path to the body field
+type Problem struct {
+	At      string `json:"at"`
+	Message string `json:"message"`
+}
+
+// RequestError lists everything wrong with the request
+type RequestError struct {
+	Problems []*Problem
+}
+
+func (e *RequestError) Error() string {
+	var messages []string
+	for _, p := range e.Problems {
+		messages = append(messages, p.At+": "+p.Message)
+	}
+	return "invalid request: " + strings.Join(messages, "; ")
+}
+
+func (e *RequestError) add(at, format string, args ...interface{}) {
+	e.Problems = append(e.Problems, &Problem{At: at, Message: fmt.Sprintf(format, args...)})
+}
+
+// ValidateRequest checks the parameters and the body of the request against the operation,
+// the body is read and put back so the handler can read it again
+func (d *Document) ValidateRequest(op *Operation, pathParams map[string]string, req *http.Request) error {
+	rErr := &RequestError{}
+	query := req.URL.Query()
+	for _, p := range op.Parameters {
+		var values []string
+		switch p.In {
+		case "path":
+			values = []string{pathParams[p.Name]}
+		case "query":
+			values = query[p.Name]
+		default:
+			continue
+		}
+		if len(values) == 0 || values[0] == "" {
+			if p.Required {
+				rErr.add(p.Name, "is required")
+			}
+			continue
+		}
+		d.validateParam(d.schema(p.Schema), values, p.Name, rErr)
+	}
+	if op.RequestBody != nil {
+		if err := d.validateBody(op.RequestBody, req, rErr); err != nil {
+			return err
+		}
+	}
+	if len(rErr.Problems) != 0 {
+		return rErr
+	}
+	return nil
+}
+
+func (d *Document) validateParam(s *Schema, values []string, at string, rErr *RequestError) {
+	if s == nil {
+		return
+	}
+	if s.Type == "array" {
+		for i, v := range values {
+			d.validateParam(d.schema(s.Items), []string{v}, fmt.Sprintf("%s[%d]", at, i), rErr)
+		}
+		return
+	}
+	if len(values) > 1 {
+		rErr.add(at, "is given more than once")
+		return
+	}
+	var v interface{} = values[0]
+	switch s.Type {
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(values[0], 64); err != nil {
+			rErr.add(at, "must be a number")
+			return
+		}
+		v = json.Number(values[0])
+	case "boolean":
+		b, err := strconv.ParseBool(values[0])
+		if err != nil {
+			rErr.add(at, "must be a boolean")
+			return
+		}
+		v = b
+	}
+	d.validateValue(s, v, at, rErr)
+}
+
+func (d *Document) validateBody(rb *RequestBody, req *http.Request, rErr *RequestError) error {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if len(bytes.TrimSpace(body)) == 0 {
+		if rb.Required {
+			rErr.add("body", "is required")
+		}
+		return nil
+	}
+	mt, ok := rb.Content["application/json"]
+	if !ok {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		rErr.add("body", "is not a valid JSON: %v", err)
+		return nil
+	}
+	d.validateValue(d.schema(mt.Schema), v, "body", rErr)
+	return nil
+}
+
+func (d *Document) validateValue(s *Schema, v interface{}, at string, rErr *RequestError) {
+	if s == nil {
+		return
+	}
+	if v == nil {
+		if !s.Nullable && (s.Type != "" || len(s.OneOf) != 0) {
+			rErr.add(at, "can't be null")
+		}
+		return
+	}
+	if len(s.OneOf) != 0 {
+		d.validateOneOf(s, v, at, rErr)
+		return
+	}
+	switch s.Type {
+	case "object":
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			rErr.add(at, "must be an object")
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				rErr.add(at+"."+name, "is required")
+			}
+		}
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if ps, ok := s.Properties[name]; ok {
+				d.validateValue(d.schema(ps), obj[name], at+"."+name, rErr)
+			}
+		}
+	case "array":
+		arr, ok := v.([]interface{})
+		if !ok {
+			rErr.add(at, "must be an array")
+			return
+		}
+		for i, item := range arr {
+			d.validateValue(d.schema(s.Items), item, fmt.Sprintf("%s[%d]", at, i), rErr)
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			rErr.add(at, "must be a string")
+			return
+		}
+		if len(s.Enum) != 0 && !contains(s.Enum, str) {
+			rErr.add(at, "must be one of %s", strings.Join(s.Enum, ", "))
+		}
+	case "integer", "number":
+		n, ok := v.(json.Number)
+		if !ok {
+			rErr.add(at, "must be a number")
+			return
+		}
+		f, err := n.Float64()
+		if err != nil {
+			rErr.add(at, "must be a number")
+			return
+		}
+		if s.Type == "integer" {
+			if _, err := n.Int64(); err != nil {
+				rErr.add(at, "must be an integer")
+				return
+			}
+		}
+		if s.Minimum != nil && f < *s.Minimum {
+			rErr.add(at, "must not be less than %v", *s.Minimum)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			rErr.add(at, "must be a boolean")
+		}
+	}
+}
+
+// validateOneOf accepts the value if exactly one of the schemas does. When none does, the problems
+// of the schema of the same type are reported as they tell more than a type mismatch
+func (d *Document) validateOneOf(s *Schema, v interface{}, at string, rErr *RequestError) {
+	var matched int
+	var closest *RequestError
+	for _, option := range s.OneOf {
+		optionErr := &RequestError{}
+		d.validateValue(d.schema(option), v, at, optionErr)
+		if len(optionErr.Problems) == 0 {
+			matched++
+			continue
+		}
+		if optionErr.Problems[0].At != at {
+			closest = optionErr
+		}
+	}
+	switch {
+	case matched == 0 && closest != nil:
+		rErr.Problems = append(rErr.Problems, closest.Problems...)
+	case matched == 0:
+		rErr.add(at, "doesn't match any of the allowed forms")
+	case matched > 1:
+		rErr.add(at, "matches more than one of the allowed forms")
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
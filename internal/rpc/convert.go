@@ -0,0 +1,273 @@
+package rpc
+
+import (
+	"encoding/json"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"hseSQL/internal"
+	pb "hseSQL/internal/catalogpb"
+	"hseSQL/internal/database"
+)
+
+// conversions between the messages and the entities, nil messages become nil entities
+
+func toPage(p *pb.Page) *database.Page {
+	if p == nil {
+		return nil
+	}
+	return &database.Page{Limit: int(p.Limit), Offset: int(p.Offset), Sort: p.Sort}
+}
+
+func toEI(ei *pb.EI) *internal.EI {
+	if ei == nil {
+		return nil
+	}
+	return &internal.EI{
+		Id:        int(ei.Id),
+		Name:      ei.Name,
+		ShortName: ei.ShortName,
+		Dimension: ei.Dimension,
+		Factor:    ei.Factor,
+		Offset:    ei.Offset,
+	}
+}
+
+func fromEI(ei *internal.EI) *pb.EI {
+	if ei == nil {
+		return nil
+	}
+	return &pb.EI{
+		Id:        int32(ei.Id),
+		Name:      ei.Name,
+		ShortName: ei.ShortName,
+		Dimension: ei.Dimension,
+		Factor:    ei.Factor,
+		Offset:    ei.Offset,
+	}
+}
+
+func toValueType(vt *pb.ValueType) *internal.ValueType {
+	if vt == nil {
+		return nil
+	}
+	res := &internal.ValueType{Id: int(vt.Id), Name: vt.Name, BaseType: vt.BaseType}
+	if res.BaseType == "" {
+		res.BaseType = internal.BaseTypeString
+	}
+	for _, v := range vt.Values {
+		res.Values = append(res.Values, &internal.EnumValue{Id: int(v.Id), Value: v.Value, Retired: v.Retired})
+	}
+	return res
+}
+
+func fromValueType(vt *internal.ValueType) *pb.ValueType {
+	res := &pb.ValueType{Id: int32(vt.Id), Name: vt.Name, BaseType: vt.BaseType}
+	for _, v := range vt.Values {
+		res.Values = append(res.Values, &pb.EnumValue{Id: int32(v.Id), Value: v.Value, Retired: v.Retired})
+	}
+	return res
+}
+
+func toValue(v *pb.Value) interface{} {
+	switch kind := v.GetKind().(type) {
+	case *pb.Value_IntegerValue:
+		return kind.IntegerValue
+	case *pb.Value_DecimalValue:
+		return kind.DecimalValue
+	case *pb.Value_BooleanValue:
+		return kind.BooleanValue
+	case *pb.Value_StringValue:
+		return kind.StringValue
+	case *pb.Value_DecimalText:
+		return json.Number(kind.DecimalText)
+	}
+	return nil
+}
+
+func fromValue(v interface{}) *pb.Value {
+	switch value := v.(type) {
+	case int64:
+		return &pb.Value{Kind: &pb.Value_IntegerValue{IntegerValue: value}}
+	case float64:
+		return &pb.Value{Kind: &pb.Value_DecimalValue{DecimalValue: value}}
+	case json.Number:
+		return &pb.Value{Kind: &pb.Value_DecimalText{DecimalText: value.String()}}
+	case bool:
+		return &pb.Value{Kind: &pb.Value_BooleanValue{BooleanValue: value}}
+	case string:
+		return &pb.Value{Kind: &pb.Value_StringValue{StringValue: value}}
+	}
+	return nil
+}
+
+func toFloat(v *wrappers.DoubleValue) *float64 {
+	if v == nil {
+		return nil
+	}
+	return &v.Value
+}
+
+func fromFloat(v *float64) *wrappers.DoubleValue {
+	if v == nil {
+		return nil
+	}
+	return &wrappers.DoubleValue{Value: *v}
+}
+
+func toInt(v *wrappers.Int32Value) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(v.Value)
+	return &i
+}
+
+func fromInt(v *int) *wrappers.Int32Value {
+	if v == nil {
+		return nil
+	}
+	return &wrappers.Int32Value{Value: int32(*v)}
+}
+
+func toConstraints(c *pb.ParamConstraints) *internal.ParamConstraints {
+	if c == nil {
+		return nil
+	}
+	return &internal.ParamConstraints{
+		Min:       toFloat(c.Min),
+		Max:       toFloat(c.Max),
+		Step:      toFloat(c.Step),
+		Precision: toInt(c.Precision),
+		MaxLength: toInt(c.MaxLength),
+	}
+}
+
+func fromConstraints(c *internal.ParamConstraints) *pb.ParamConstraints {
+	if c == nil {
+		return nil
+	}
+	return &pb.ParamConstraints{
+		Min:       fromFloat(c.Min),
+		Max:       fromFloat(c.Max),
+		Step:      fromFloat(c.Step),
+		Precision: fromInt(c.Precision),
+		MaxLength: fromInt(c.MaxLength),
+	}
+}
+
+func toParam(p *pb.Param) *internal.Param {
+	if p == nil {
+		return nil
+	}
+	return &internal.Param{
+		Id:          int(p.Id),
+		Name:        p.Name,
+		ValType:     p.ValType,
+		BaseType:    p.BaseType,
+		EI:          toEI(p.Ei),
+		Constraints: toConstraints(p.Constraints),
+		Required:    p.Required,
+		Default:     toValue(p.DefaultValue),
+		DisplayEI:   toEI(p.DisplayEi),
+	}
+}
+
+func fromParam(p *internal.Param) *pb.Param {
+	return &pb.Param{
+		Id:           int32(p.Id),
+		Name:         p.Name,
+		ValType:      p.ValType,
+		BaseType:     p.BaseType,
+		Ei:           fromEI(p.EI),
+		Constraints:  fromConstraints(p.Constraints),
+		Required:     p.Required,
+		DefaultValue: fromValue(p.Default),
+		DisplayEi:    fromEI(p.DisplayEI),
+	}
+}
+
+func toOverride(o *pb.ParamOverride) *internal.ParamOverride {
+	res := &internal.ParamOverride{
+		Name:        o.Name,
+		Constraints: toConstraints(o.Constraints),
+		Default:     toValue(o.DefaultValue),
+		DisplayEI:   toEI(o.DisplayEi),
+	}
+	if o.Required != nil {
+		res.Required = &o.Required.Value
+	}
+	return res
+}
+
+func toClass(c *pb.Class) *internal.Class {
+	if c == nil {
+		return nil
+	}
+	res := &internal.Class{
+		Id:   int(c.Id),
+		Name: c.Name,
+		Ei:   toEI(c.Ei),
+	}
+	for _, child := range c.Children {
+		res.Children = append(res.Children, toClass(child))
+	}
+	for _, p := range c.Params {
+		res.Params = append(res.Params, toParam(p))
+	}
+	for _, o := range c.Overrides {
+		res.Overrides = append(res.Overrides, toOverride(o))
+	}
+	return res
+}
+
+func fromClass(c *internal.Class) *pb.Class {
+	if c == nil {
+		return nil
+	}
+	res := &pb.Class{
+		Id:   int32(c.Id),
+		Name: c.Name,
+		Ei:   fromEI(c.Ei),
+	}
+	for _, child := range c.Children {
+		res.Children = append(res.Children, fromClass(child))
+	}
+	for _, p := range c.Params {
+		res.Params = append(res.Params, fromParam(p))
+	}
+	return res
+}
+
+func toProduct(p *pb.Product) *internal.Product {
+	if p == nil {
+		return nil
+	}
+	res := &internal.Product{
+		Id:          int(p.Id),
+		Name:        p.Name,
+		ParentClass: toClass(p.ParentClass),
+	}
+	for _, pv := range p.Params {
+		res.Params = append(res.Params, &internal.ParamAndValues{
+			Param: toParam(pv.Param),
+			Value: toValue(pv.Value),
+			Unit:  pv.Unit,
+		})
+	}
+	return res
+}
+
+func fromProduct(p *internal.Product) *pb.Product {
+	res := &pb.Product{
+		Id:          int32(p.Id),
+		Name:        p.Name,
+		ParentClass: fromClass(p.ParentClass),
+	}
+	for _, pv := range p.Params {
+		res.Params = append(res.Params, &pb.ParamValue{
+			Param: fromParam(pv.Param),
+			Value: fromValue(pv.Value),
+			Unit:  pv.Unit,
+		})
+	}
+	return res
+}
@@ -0,0 +1,45 @@
+package rpc
+
+import (
+	"errors"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"hseSQL/internal/database"
+	"strings"
+)
+
+// statusError turns the errors of the database operator into the statuses, it follows
+// the classification of the http api: errors nobody expected are not shown to the client
+func statusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	log.Error(err)
+	var vErr *database.ValidationError
+	var iErr *database.InvalidErr
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.As(err, &vErr):
+		return status.Error(codes.InvalidArgument, vErr.Error())
+	case errors.As(err, &iErr):
+		if iErr.Conflict {
+			return status.Error(codes.FailedPrecondition, iErr.Error())
+		}
+		return status.Error(codes.InvalidArgument, iErr.Error())
+	case errors.Is(err, database.ErrVersionMismatch):
+		return status.Error(codes.Aborted, database.ErrVersionMismatch.Error())
+	case errors.Is(err, pgx.ErrNoRows):
+		return status.Error(codes.NotFound, "not found")
+	case errors.As(err, &pgErr):
+		switch {
+		case pgErr.Code == "23505":
+			return status.Error(codes.AlreadyExists, pgErr.Message)
+		case pgErr.Code == "23503", pgErr.Code == "23502", pgErr.Code == "23514", strings.HasPrefix(pgErr.Code, "22"):
+			return status.Error(codes.InvalidArgument, pgErr.Message)
+		}
+	}
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,192 @@
+// Package rpc serves the catalog over gRPC next to the http api, both work over the same DbOperator
+package rpc
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"hseSQL/internal"
+	pb "hseSQL/internal/catalogpb"
+	"hseSQL/internal/database"
+)
+
+// listProductsBatch is the number of products ListProducts reads at once
+const listProductsBatch = 100
+
+type Server struct {
+	do *database.DbOperator
+}
+
+func NewServer(do *database.DbOperator) *grpc.Server {
+	s := grpc.NewServer()
+	pb.RegisterCatalogServer(s, &Server{do: do})
+	return s
+}
+
+func (s *Server) CreateEIs(ctx context.Context, req *pb.CreateEIsRequest) (*pb.IdsResponse, error) {
+	var eis []*internal.EI
+	for _, ei := range req.Eis {
+		eis = append(eis, toEI(ei))
+	}
+	ids, err := s.do.CreateAndReadEIs(eis)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	res := &pb.IdsResponse{}
+	for _, id := range ids {
+		res.Ids = append(res.Ids, int32(id))
+	}
+	return res, nil
+}
+
+func (s *Server) ListEIs(ctx context.Context, req *pb.ListEIsRequest) (*pb.ListEIsResponse, error) {
+	eis, total, err := s.do.ReadEI(req.Name, toPage(req.Page))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	res := &pb.ListEIsResponse{Total: int32(total)}
+	for _, ei := range eis {
+		res.Eis = append(res.Eis, fromEI(ei))
+	}
+	return res, nil
+}
+
+func (s *Server) CreateValueTypes(ctx context.Context, req *pb.CreateValueTypesRequest) (*pb.Empty, error) {
+	var vts []*internal.ValueType
+	for _, vt := range req.ValueTypes {
+		vts = append(vts, toValueType(vt))
+	}
+	if err := s.do.CreateValueTypes(vts); err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Empty{}, nil
+}
+
+func (s *Server) ListValueTypes(ctx context.Context, req *pb.ListValueTypesRequest) (*pb.ListValueTypesResponse, error) {
+	vts, total, err := s.do.ReadValueTypes(toPage(req.Page))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	res := &pb.ListValueTypesResponse{Total: int32(total)}
+	for _, vt := range vts {
+		res.ValueTypes = append(res.ValueTypes, fromValueType(vt))
+	}
+	return res, nil
+}
+
+func (s *Server) CreateClasses(ctx context.Context, req *pb.CreateClassesRequest) (*pb.Empty, error) {
+	var cc []*internal.Class
+	for _, c := range req.Classes {
+		cc = append(cc, toClass(c))
+	}
+	if err := s.do.CreateClasses(cc); err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Empty{}, nil
+}
+
+func (s *Server) GetClass(ctx context.Context, req *pb.GetClassRequest) (*pb.Class, error) {
+	c, err := s.do.ReadClass(int(req.Id), req.AllParams)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return fromClass(c), nil
+}
+
+func (s *Server) GetClassTree(ctx context.Context, req *pb.Empty) (*pb.ClassTree, error) {
+	cc, err := s.do.ReadClassTree()
+	if err != nil {
+		return nil, statusError(err)
+	}
+	res := &pb.ClassTree{}
+	for _, c := range cc {
+		res.Classes = append(res.Classes, fromClass(c))
+	}
+	return res, nil
+}
+
+func (s *Server) DeleteClass(ctx context.Context, req *pb.DeleteClassRequest) (*pb.Empty, error) {
+	if err := s.do.DeleteClass(int(req.Id), nil); err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Empty{}, nil
+}
+
+func (s *Server) CreateProducts(ctx context.Context, req *pb.CreateProductsRequest) (*pb.Empty, error) {
+	var pp []*internal.Product
+	for _, p := range req.Products {
+		if err := checkProduct(p); err != nil {
+			return nil, err
+		}
+		pp = append(pp, toProduct(p))
+	}
+	if err := s.do.CreateProducts(pp); err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Empty{}, nil
+}
+
+func (s *Server) GetProduct(ctx context.Context, req *pb.GetProductRequest) (*pb.Product, error) {
+	p, err := s.do.ReadProduct(int(req.Id), req.Unit)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return fromProduct(p), nil
+}
+
+func (s *Server) UpdateProduct(ctx context.Context, req *pb.UpdateProductRequest) (*pb.Empty, error) {
+	if req.Product == nil {
+		return nil, status.Error(codes.InvalidArgument, "product is missing")
+	}
+	if err := checkProduct(req.Product); err != nil {
+		return nil, err
+	}
+	if err := s.do.UpdateProduct(toProduct(req.Product), nil); err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Empty{}, nil
+}
+
+func (s *Server) DeleteProduct(ctx context.Context, req *pb.DeleteProductRequest) (*pb.Empty, error) {
+	if err := s.do.DeleteProduct(int(req.Id), nil); err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Empty{}, nil
+}
+
+// ListProducts reads the products by batches, so a large class is not kept in memory at once
+func (s *Server) ListProducts(req *pb.ListProductsRequest, stream pb.Catalog_ListProductsServer) error {
+	var sendErr error
+	send := func(pp []*internal.Product) error {
+		if err := stream.Context().Err(); err != nil {
+			sendErr = status.FromContextError(err).Err()
+			return sendErr
+		}
+		for _, p := range pp {
+			if err := stream.Send(fromProduct(p)); err != nil {
+				sendErr = err
+				return err
+			}
+		}
+		return nil
+	}
+	err := s.do.ReadClassProductsBatches(int(req.ClassId), req.Unit, req.Sort, listProductsBatch, send)
+	if sendErr != nil {
+		return sendErr
+	}
+	if err != nil {
+		return statusError(err)
+	}
+	return nil
+}
+
+// checkProduct rejects the values without a param, the database operator expects every value to have one
+func checkProduct(p *pb.Product) error {
+	for _, pv := range p.Params {
+		if pv.Param == nil {
+			return status.Errorf(codes.InvalidArgument, "a value of product %s has no param", p.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"hseSQL/internal/database"
+	"net/http"
+	"strconv"
+)
+
+// ExportCatalog responds with the dump of the whole catalog as an attachment
+func (r *Runner) ExportCatalog(w http.ResponseWriter, req *http.Request) {
+	d, err := r.do.DumpCatalog()
+	if err != nil {
+		log.Error(err)
+		writeError(w, err)
+		return
+	}
+	filename := strconv.Quote(fmt.Sprintf("catalog-%s.json", d.Created.Format("20060102-150405")))
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	if err := json.NewEncoder(w).Encode(d); err != nil {
+		log.Error(err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+}
+
+// ImportCatalog restores the dump and responds with the ids the items of the dump got
+func (r *Runner) ImportCatalog(w http.ResponseWriter, req *http.Request) {
+	d, err := database.DecodeDump(req.Body)
+	if err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	report, err := r.do.RestoreCatalog(d)
+	if err != nil {
+		log.Error(err)
+		writeError(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Error(err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+}
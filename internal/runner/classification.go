@@ -0,0 +1,30 @@
+package runner
+
+import (
+	"encoding/json"
+	log "github.com/sirupsen/logrus"
+	"hseSQL/internal/classification"
+	"net/http"
+)
+
+// ImportClassification builds the class tree from a release of a classification standard,
+// the next releases of the same system update the tree the first one made
+func (r *Runner) ImportClassification(w http.ResponseWriter, req *http.Request) {
+	rel, err := classification.Read(req.Body)
+	if err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	report, err := r.do.ImportClassification(rel, req.URL.Query().Get("unit"))
+	if err != nil {
+		log.Error(err)
+		writeError(w, err)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Error(err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+}
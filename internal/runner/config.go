@@ -9,6 +9,8 @@ import (
 
 type Config struct {
 	ServerAddr string   `yaml:"server_addr"`
+	// GrpcAddr is where the gRPC api listens, it is not started without the address
+	GrpcAddr string `yaml:"grpc_addr"`
 	DbConfig *database.Config `yaml:"db_config"`
 }
 
@@ -0,0 +1,71 @@
+package runner
+
+import (
+	"encoding/json"
+	log "github.com/sirupsen/logrus"
+	swaggerFiles "github.com/swaggo/files/v2"
+	"net/http"
+)
+
+var docsAssets = http.StripPrefix("/docs/assets/", http.FileServer(http.FS(swaggerFiles.FS)))
+
+// docsPage shows the specification with swagger ui, the assets are served by the service
+// so the docs work without access to the internet
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<title>hseSQL API</title>
+	<link rel="stylesheet" href="/docs/assets/swagger-ui.css">
+</head>
+<body>
+	<div id="swagger-ui"></div>
+	<script src="/docs/assets/swagger-ui-bundle.js"></script>
+	<script>
+		window.onload = function () {
+			window.ui = SwaggerUIBundle({url: "/openapi.json", dom_id: "#swagger-ui"});
+		};
+	</script>
+</body>
+</html>
+`
+
+// validateRequest rejects the requests that don't match the specification before they reach the handlers,
+// the routes the specification doesn't describe are passed as they are
+func (r *Runner) validateRequest(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		op, pathParams := r.spec.Find(req.Method, req.URL.Path)
+		if op == nil {
+			next.ServeHTTP(w, req)
+			return
+		}
+		if err := r.spec.ValidateRequest(op, pathParams, req); err != nil {
+			log.Error(err)
+			writeError(w, err)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+func (r *Runner) GetSpec(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(r.spec); err != nil {
+		log.Error(err)
+		return
+	}
+	return
+}
+
+func (r *Runner) GetDocs(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if _, err := w.Write([]byte(docsPage)); err != nil {
+		log.Error(err)
+		return
+	}
+	return
+}
+
+func (r *Runner) GetDocsAssets(w http.ResponseWriter, req *http.Request) {
+	docsAssets.ServeHTTP(w, req)
+}
@@ -0,0 +1,120 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	log "github.com/sirupsen/logrus"
+	"hseSQL/internal/database"
+	"hseSQL/internal/openapi"
+	"net/http"
+	"strings"
+)
+
+// error codes of the responses
+const (
+	codeBadRequest         = "bad_request"
+	codeNotFound           = "not_found"
+	codeConflict           = "conflict"
+	codeInvalid            = "invalid"
+	codeValidationFailed   = "validation_failed"
+	codePreconditionFailed = "precondition_failed"
+	codeInternal           = "internal"
+)
+
+// apiError is the body of every failed response
+type apiError struct {
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// requestErr is an error of reading the request itself: a broken body or a malformed query param
+type requestErr struct {
+	err error
+}
+
+func newRequestErr(err error) error {
+	return &requestErr{err: err}
+}
+
+func (e *requestErr) Error() string {
+	return e.err.Error()
+}
+
+func (e *requestErr) Unwrap() error {
+	return e.err
+}
+
+// classifyError picks the status and the body of the response for the error,
+// errors nobody expected are not shown to the client
+func classifyError(err error) (int, *apiError) {
+	var rErr *requestErr
+	var sErr *openapi.RequestError
+	var vErr *database.ValidationError
+	var iErr *database.InvalidErr
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.As(err, &rErr):
+		return http.StatusBadRequest, &apiError{Code: codeBadRequest, Message: rErr.Error()}
+	case errors.As(err, &sErr):
+		return http.StatusBadRequest, &apiError{
+			Code:    codeBadRequest,
+			Message: "the request doesn't match the api specification",
+			Details: sErr.Problems,
+		}
+	case errors.As(err, &vErr):
+		return http.StatusUnprocessableEntity, &apiError{
+			Code:    codeValidationFailed,
+			Message: "some values are rejected",
+			Details: vErr.Fields,
+		}
+	case errors.As(err, &iErr):
+		if iErr.Conflict {
+			return http.StatusConflict, &apiError{Code: codeConflict, Message: iErr.Error()}
+		}
+		return http.StatusUnprocessableEntity, &apiError{Code: codeInvalid, Message: iErr.Error()}
+	case errors.Is(err, database.ErrVersionMismatch):
+		return http.StatusPreconditionFailed, &apiError{Code: codePreconditionFailed, Message: database.ErrVersionMismatch.Error()}
+	case errors.Is(err, pgx.ErrNoRows):
+		// the references of a created or changed item that are missing are reported
+		// as InvalidErr, so this is the item the request addresses
+		return http.StatusNotFound, &apiError{Code: codeNotFound, Message: "not found"}
+	case errors.As(err, &pgErr):
+		return classifyPgError(pgErr)
+	}
+	return http.StatusInternalServerError, &apiError{Code: codeInternal, Message: "internal error"}
+}
+
+// classifyPgError maps the integrity constraint and the data exception errors
+// of postgres to the client errors
+func classifyPgError(pgErr *pgconn.PgError) (int, *apiError) {
+	message := pgErr.Message
+	if pgErr.Detail != "" {
+		message += ": " + pgErr.Detail
+	}
+	switch {
+	case pgErr.Code == "23505":
+		return http.StatusConflict, &apiError{Code: codeConflict, Message: message}
+	case pgErr.Code == "23503" && pgErr.TableName != "" && strings.Contains(pgErr.Message, "update or delete"):
+		// the row is still referenced
+		return http.StatusConflict, &apiError{Code: codeConflict, Message: message}
+	case pgErr.Code == "23503", pgErr.Code == "23502", pgErr.Code == "23514", strings.HasPrefix(pgErr.Code, "22"):
+		return http.StatusUnprocessableEntity, &apiError{Code: codeInvalid, Message: message}
+	}
+	return http.StatusInternalServerError, &apiError{Code: codeInternal, Message: "internal error"}
+}
+
+// writeError responds with the error envelope, the status depends on the kind of the error
+func writeError(w http.ResponseWriter, err error) {
+	status, apiErr := classifyError(err)
+	type response struct {
+		Error *apiError `json:"error"`
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(&response{Error: apiErr}); err != nil {
+		log.Error(err)
+	}
+}
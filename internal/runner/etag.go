@@ -0,0 +1,37 @@
+package runner
+
+import (
+	"hseSQL/internal/database"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// setETag exposes the version of a class or a product, so the client can send it back in If-Match
+func setETag(w http.ResponseWriter, version int) {
+	w.Header().Set("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// readIfMatch turns If-Match into the versions the change expects, without the header or with *
+// there is no precondition. Weak and foreign tags never match, as If-Match compares them strongly
+func readIfMatch(req *http.Request) *database.Precondition {
+	header := strings.TrimSpace(req.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return nil
+	}
+	pc := &database.Precondition{}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if !strings.HasPrefix(tag, `"`) {
+			continue
+		}
+		unquoted, err := strconv.Unquote(tag)
+		if err != nil {
+			continue
+		}
+		if version, err := strconv.Atoi(unquoted); err == nil {
+			pc.Versions = append(pc.Versions, version)
+		}
+	}
+	return pc
+}
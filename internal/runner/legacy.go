@@ -0,0 +1,26 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+)
+
+// apiV1 is the prefix of the resource routes
+const apiV1 = "/api/v1"
+
+type legacyKey struct{}
+
+// deprecated marks the responses of the legacy routes, handlers that answer
+// the legacy clients differently check the route with isLegacy
+func deprecated(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", "<"+apiV1+">; rel=\"successor-version\"")
+		next.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), legacyKey{}, true)))
+	})
+}
+
+func isLegacy(req *http.Request) bool {
+	legacy, _ := req.Context().Value(legacyKey{}).(bool)
+	return legacy
+}
@@ -0,0 +1,59 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"hseSQL/internal/database"
+	"net/http"
+)
+
+// modes of the bulk create routes: the whole batch in one transaction or every item on its own
+const (
+	modeAtomic  = "atomic"
+	modePartial = "partial"
+)
+
+// readMode tells if the batch is created in the partial mode, the atomic one is the default
+func readMode(req *http.Request) (partial bool, err error) {
+	switch mode := req.URL.Query().Get("mode"); mode {
+	case "", modeAtomic:
+		return false, nil
+	case modePartial:
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown mode %s", mode)
+	}
+}
+
+// itemResult is the outcome of one item of the batch in the response
+type itemResult struct {
+	Index int       `json:"index"`
+	Id    int       `json:"id,omitempty"`
+	Error *apiError `json:"error,omitempty"`
+}
+
+// writeItemResults responds with the results of the batch in the order of the request items
+func writeItemResults(w http.ResponseWriter, results []*database.ItemResult) {
+	type response struct {
+		Results []*itemResult `json:"results"`
+		Created int           `json:"created"`
+		Failed  int           `json:"failed"`
+	}
+	res := &response{Results: make([]*itemResult, 0, len(results))}
+	for i, r := range results {
+		item := &itemResult{Index: i, Id: r.Id}
+		if r.Err != nil {
+			log.Error(r.Err)
+			_, item.Error = classifyError(r.Err)
+			res.Failed++
+		} else {
+			res.Created++
+		}
+		res.Results = append(res.Results, item)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		log.Error(err)
+	}
+}
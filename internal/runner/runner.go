@@ -2,22 +2,35 @@ package runner
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/go-chi/chi"
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
 	"hseSQL/internal"
 	"hseSQL/internal/database"
+	"hseSQL/internal/graph"
+	"hseSQL/internal/openapi"
+	"hseSQL/internal/rpc"
+	"net"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 type Runner struct {
 	do     *database.DbOperator
 	server *http.Server
 	router *chi.Mux
+	spec   *openapi.Document
+	gql    *graph.Handler
+	// grpc serves the same operations over gRPC when the config has its address
+	grpc     *grpc.Server
+	grpcAddr string
 }
 
-func NewRunner(config *Config) (*Runner, error) {
+// OpenDb connects to the database of the config and creates the missing tables
+func OpenDb(config *Config) (*database.DbOperator, error) {
 	cs, err := database.NewConnectionService(config.DbConfig)
 	if err != nil {
 		return nil, err
@@ -26,57 +39,198 @@ func NewRunner(config *Config) (*Runner, error) {
 	if err := do.CreateTables(); err != nil {
 		return nil, err
 	}
+	return do, nil
+}
+
+func NewRunner(config *Config) (*Runner, error) {
+	do, err := OpenDb(config)
+	if err != nil {
+		return nil, err
+	}
 	r := &Runner{
-		do: do,
+		do:   do,
+		spec: apiSpec(),
+		gql:  graph.NewHandler(do),
 	}
 	r.AddRouter()
 	r.server = &http.Server{
 		Addr:    config.ServerAddr,
 		Handler: r.router,
 	}
+	if config.GrpcAddr != "" {
+		r.grpcAddr = config.GrpcAddr
+		r.grpc = rpc.NewServer(do)
+	}
 	return r, nil
 }
 
 func (r *Runner) AddRouter() {
 	router := chi.NewRouter()
-	router.Post("/ei", r.AddEi)
-	router.Get("/ei", r.GetEi)
-
-	router.Post("/valuetype", r.AddVT)
-	router.Get("/valuetype", r.GetVT)
-
-	router.Post("/class", r.AddC)
-	router.Get("/class", r.GetC)
-	router.Get("/classtree", r.GetCTree)
-	router.Get("/classchildren", r.GetCChildren)
-	router.Delete("/class", r.DeleteC)
-
-	router.Post("/product", r.AddP)
-	router.Get("/product", r.GetP)
-	router.Get("/productclass", r.GetPC)
-	router.Put("/product", r.UpdateP)
-	router.Delete("/product", r.DeletePC)
+	router.Use(r.validateRequest)
+	router.Get("/openapi.json", r.GetSpec)
+	router.Get("/docs", r.GetDocs)
+	router.Get("/docs/assets/*", r.GetDocsAssets)
+	router.Get("/admin/export", r.ExportCatalog)
+	router.Post("/admin/import", r.ImportCatalog)
+
+	router.Route(apiV1, func(v1 chi.Router) {
+		v1.Post("/units", r.AddEi)
+		v1.Get("/units", r.GetEi)
+		v1.Put("/units/{name}", r.UpdateEi)
+
+		v1.Post("/dimensions", r.AddDim)
+		v1.Get("/dimensions", r.GetDim)
+
+		v1.Post("/value-types", r.AddVT)
+		v1.Get("/value-types", r.GetVT)
+		v1.Post("/value-types/{name}/values", r.AddVTValues)
+		v1.Put("/value-types/{name}/values/{value}", r.UpdateVTValue)
+		v1.Delete("/value-types/{name}/values/{value}", r.DeleteVTValue)
+
+		v1.Post("/params", r.AddParam)
+		v1.Get("/params", r.GetParam)
+		v1.Get("/params/{id}/classes", r.GetParamClasses)
+
+		v1.Post("/classes", r.AddC)
+		v1.Get("/classes", r.GetCTree)
+		v1.Get("/classes/{id}", r.GetC)
+		v1.Put("/classes/{id}", r.UpdateC)
+		v1.Delete("/classes/{id}", r.DeleteC)
+		v1.Get("/classes/{id}/children", r.GetCChildren)
+		v1.Post("/classes/{id}/move", r.MoveC)
+		v1.Get("/classes/{id}/products", r.GetPC)
+		v1.Get("/classes/{id}/products/search", r.SearchP)
+		v1.Post("/classes/{id}/products/import", r.ImportPC)
+		v1.Get("/classes/{id}/products/export", r.ExportPC)
+
+		v1.Post("/products", r.AddP)
+		v1.Get("/products/{id}", r.GetP)
+		v1.Put("/products/{id}", r.UpdateP)
+		v1.Patch("/products/{id}", r.PatchP)
+		v1.Delete("/products/{id}", r.DeletePC)
+
+		v1.Post("/classifications/import", r.ImportClassification)
+
+		v1.Get("/search", r.Search)
+		v1.Post("/graphql", r.gql.ServeHTTP)
+	})
+
+	// the routes before /api/v1 are kept for the existing clients
+	router.Group(func(legacy chi.Router) {
+		legacy.Use(deprecated)
+		legacy.Post("/ei", r.AddEi)
+		legacy.Get("/ei", r.GetEi)
+
+		legacy.Post("/dimension", r.AddDim)
+		legacy.Get("/dimension", r.GetDim)
+
+		legacy.Post("/valuetype", r.AddVT)
+		legacy.Get("/valuetype", r.GetVT)
+		legacy.Post("/valuetype/values", r.AddVTValues)
+		legacy.Put("/valuetype/values", r.UpdateVTValue)
+		legacy.Delete("/valuetype/values", r.DeleteVTValue)
+
+		legacy.Post("/param", r.AddParam)
+		legacy.Get("/param", r.GetParam)
+		legacy.Get("/param/{id}/classes", r.GetParamClasses)
+
+		legacy.Post("/class", r.AddC)
+		legacy.Get("/class", r.GetC)
+		legacy.Get("/classtree", r.GetCTree)
+		legacy.Get("/classchildren", r.GetCChildren)
+		legacy.Delete("/class", r.DeleteC)
+		legacy.Put("/class/{id}", r.UpdateC)
+		legacy.Post("/class/{id}/move", r.MoveC)
+
+		legacy.Post("/product", r.AddP)
+		legacy.Get("/product", r.GetP)
+		legacy.Get("/productclass", r.GetPC)
+		legacy.Put("/product", r.UpdateP)
+		legacy.Patch("/product/{id}", r.PatchP)
+		legacy.Delete("/product", r.DeletePC)
+		legacy.Get("/products/search", r.SearchP)
+
+		legacy.Get("/search", r.Search)
+	})
 	r.router = router
 }
 
 func (r *Runner) Run() {
 	fmt.Println("starting")
+	if r.grpc != nil {
+		lis, err := net.Listen("tcp", r.grpcAddr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		go func() {
+			if err := r.grpc.Serve(lis); err != nil {
+				log.Fatal(err)
+			}
+		}()
+	}
 	if err := r.server.ListenAndServe(); err != nil {
 		log.Fatal(err)
 	}
 }
 
+// readPage reads the limit, offset and sort query params, paged tells if any of them was set
+func readPage(req *http.Request) (page *database.Page, paged bool, err error) {
+	q := req.URL.Query()
+	page = &database.Page{Sort: q.Get("sort")}
+	if l := q.Get("limit"); l != "" {
+		if page.Limit, err = strconv.Atoi(l); err != nil {
+			return nil, false, err
+		}
+	}
+	if o := q.Get("offset"); o != "" {
+		if page.Offset, err = strconv.Atoi(o); err != nil {
+			return nil, false, err
+		}
+	}
+	if page.Limit < 0 || page.Offset < 0 {
+		return nil, false, errors.New("limit and offset can't be negative")
+	}
+	paged = q.Get("limit") != "" || q.Get("offset") != "" || page.Sort != ""
+	return page, paged, nil
+}
+
+// readId reads the id from the path of the resource routes or from the query param of the legacy ones
+func readId(req *http.Request, queryName string) (int, error) {
+	if id := chi.URLParam(req, "id"); id != "" {
+		return strconv.Atoi(id)
+	}
+	return strconv.Atoi(req.URL.Query().Get(queryName))
+}
+
+// pathOrQuery reads a value from the path of the resource routes or from the query param of the legacy ones
+func pathOrQuery(req *http.Request, pathName, queryName string) string {
+	if v := chi.URLParam(req, pathName); v != "" {
+		return v
+	}
+	return req.URL.Query().Get(queryName)
+}
+
 func (r *Runner) AddEi(w http.ResponseWriter, req *http.Request) {
 	var re []*internal.EI
 	if err := json.NewDecoder(req.Body).Decode(&re); err != nil {
 		log.Error(err)
-		w.WriteHeader(http.StatusBadRequest)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	partial, err := readMode(req)
+	if err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	if partial {
+		writeItemResults(w, r.do.CreateEIsPartially(re))
 		return
 	}
 	ids, err := r.do.CreateAndReadEIs(re)
 	if err != nil {
 		log.Error(err)
-		w.WriteHeader(http.StatusBadRequest)
+		writeError(w, err)
 		return
 	}
 	type response struct {
@@ -93,13 +247,77 @@ func (r *Runner) AddEi(w http.ResponseWriter, req *http.Request) {
 
 func (r *Runner) GetEi(w http.ResponseWriter, req *http.Request) {
 	eiName := req.URL.Query().Get("ei_name")
-	eis, err := r.do.ReadEI(eiName)
+	page, paged, err := readPage(req)
 	if err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	eis, total, err := r.do.ReadEI(eiName, page)
+	if err != nil {
+		log.Error(err)
+		writeError(w, err)
+		return
+	}
+	// the plain list is kept for the legacy clients that don't page
+	var res interface{} = eis
+	if paged || !isLegacy(req) {
+		type response struct {
+			EIs    []*internal.EI `json:"eis"`
+			Total  int            `json:"total"`
+			Limit  int            `json:"limit"`
+			Offset int            `json:"offset"`
+		}
+		res = &response{EIs: eis, Total: total, Limit: page.Limit, Offset: page.Offset}
+	}
+	if err := json.NewEncoder(w).Encode(res); err != nil {
 		log.Error(err)
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
-	if err := json.NewEncoder(w).Encode(eis); err != nil {
+	return
+}
+
+func (r *Runner) AddDim(w http.ResponseWriter, req *http.Request) {
+	type request struct {
+		Dimensions []string `json:"dimensions"`
+	}
+	re := &request{}
+	if err := json.NewDecoder(req.Body).Decode(&re); err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	ids, err := r.do.CreateDimensions(re.Dimensions)
+	if err != nil {
+		log.Error(err)
+		writeError(w, err)
+		return
+	}
+	type response struct {
+		Ids []int `json:"ids"`
+	}
+	res := &response{Ids: ids}
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		log.Error(err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	return
+}
+
+func (r *Runner) GetDim(w http.ResponseWriter, req *http.Request) {
+	dd, err := r.do.ReadDimensions()
+	if err != nil {
+		log.Error(err)
+		writeError(w, err)
+		return
+	}
+	type response struct {
+		Dimensions []*internal.Dimension `json:"dimensions"`
+	}
+	res := &response{Dimensions: dd}
+	if err := json.NewEncoder(w).Encode(res); err != nil {
 		log.Error(err)
 		w.WriteHeader(http.StatusBadRequest)
 		return
@@ -109,33 +327,42 @@ func (r *Runner) GetEi(w http.ResponseWriter, req *http.Request) {
 
 func (r *Runner) AddVT(w http.ResponseWriter, req *http.Request) {
 	type request struct {
-		ValueTypes []string `json:"value_types"`
+		ValueTypes []*internal.ValueType `json:"value_types"`
 	}
 	re := &request{}
 	if err := json.NewDecoder(req.Body).Decode(&re); err != nil {
 		log.Error(err)
-		w.WriteHeader(http.StatusBadRequest)
+		writeError(w, newRequestErr(err))
 		return
 	}
 	if err := r.do.CreateValueTypes(re.ValueTypes); err != nil {
 		log.Error(err)
-		w.WriteHeader(http.StatusBadRequest)
+		writeError(w, err)
 		return
 	}
 	return
 }
 
 func (r *Runner) GetVT(w http.ResponseWriter, req *http.Request) {
-	vts, err := r.do.ReadValueTypes()
+	page, _, err := readPage(req)
 	if err != nil {
 		log.Error(err)
-		w.WriteHeader(http.StatusBadRequest)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	vts, total, err := r.do.ReadValueTypes(page)
+	if err != nil {
+		log.Error(err)
+		writeError(w, err)
 		return
 	}
 	type response struct {
-		ValueTypes []string `json:"value_types"`
+		ValueTypes []*internal.ValueType `json:"value_types"`
+		Total      int                   `json:"total"`
+		Limit      int                   `json:"limit"`
+		Offset     int                   `json:"offset"`
 	}
-	res := &response{ValueTypes:vts}
+	res := &response{ValueTypes: vts, Total: total, Limit: page.Limit, Offset: page.Offset}
 	if err := json.NewEncoder(w).Encode(res); err != nil {
 		log.Error(err)
 		w.WriteHeader(http.StatusBadRequest)
@@ -144,17 +371,98 @@ func (r *Runner) GetVT(w http.ResponseWriter, req *http.Request) {
 	return
 }
 
-func (r *Runner) AddC(w http.ResponseWriter, req *http.Request) {
+func (r *Runner) AddVTValues(w http.ResponseWriter, req *http.Request) {
 	type request struct {
-		Classes []*internal.Class `json:"classes"`
+		ValueType string   `json:"value_type"`
+		Values    []string `json:"values"`
 	}
 	re := &request{}
 	if err := json.NewDecoder(req.Body).Decode(&re); err != nil {
 		log.Error(err)
-		w.WriteHeader(http.StatusBadRequest)
+		writeError(w, newRequestErr(err))
 		return
 	}
-	if err := r.do.CreateClasses(re.Classes); err != nil {
+	if name := chi.URLParam(req, "name"); name != "" {
+		re.ValueType = name
+	}
+	if err := r.do.AddEnumValues(re.ValueType, re.Values); err != nil {
+		log.Error(err)
+		writeError(w, err)
+		return
+	}
+	return
+}
+
+func (r *Runner) UpdateEi(w http.ResponseWriter, req *http.Request) {
+	re := &internal.EI{}
+	if err := json.NewDecoder(req.Body).Decode(re); err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	if err := r.do.UpdateEI(chi.URLParam(req, "name"), re); err != nil {
+		log.Error(err)
+		writeError(w, err)
+		return
+	}
+	return
+}
+
+func (r *Runner) UpdateVTValue(w http.ResponseWriter, req *http.Request) {
+	type request struct {
+		ValueType string `json:"value_type"`
+		Value     string `json:"value"`
+		NewValue  string `json:"new_value"`
+	}
+	re := &request{}
+	if err := json.NewDecoder(req.Body).Decode(&re); err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	if name := chi.URLParam(req, "name"); name != "" {
+		re.ValueType, re.Value = name, chi.URLParam(req, "value")
+	}
+	if err := r.do.RenameEnumValue(re.ValueType, re.Value, re.NewValue); err != nil {
+		log.Error(err)
+		writeError(w, err)
+		return
+	}
+	return
+}
+
+func (r *Runner) DeleteVTValue(w http.ResponseWriter, req *http.Request) {
+	valueType := pathOrQuery(req, "name", "value_type")
+	value := pathOrQuery(req, "value", "value")
+	if err := r.do.RetireEnumValue(valueType, value); err != nil {
+		log.Error(err)
+		writeError(w, err)
+		return
+	}
+	return
+}
+
+func (r *Runner) AddParam(w http.ResponseWriter, req *http.Request) {
+	type request struct {
+		Params []*internal.Param `json:"params"`
+	}
+	re := &request{}
+	if err := json.NewDecoder(req.Body).Decode(&re); err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	ids, err := r.do.CreateParams(re.Params)
+	if err != nil {
+		log.Error(err)
+		writeError(w, err)
+		return
+	}
+	type response struct {
+		Ids []int `json:"ids"`
+	}
+	res := &response{Ids: ids}
+	if err := json.NewEncoder(w).Encode(res); err != nil {
 		log.Error(err)
 		w.WriteHeader(http.StatusBadRequest)
 		return
@@ -162,27 +470,103 @@ func (r *Runner) AddC(w http.ResponseWriter, req *http.Request) {
 	return
 }
 
-func (r *Runner) GetC(w http.ResponseWriter, req *http.Request) {
-	idClass := req.URL.Query().Get("class_id")
-	id, err := strconv.Atoi(idClass)
+func (r *Runner) GetParam(w http.ResponseWriter, req *http.Request) {
+	paramName := req.URL.Query().Get("param_name")
+	pp, err := r.do.ReadParams(paramName)
 	if err != nil {
+		log.Error(err)
+		writeError(w, err)
+		return
+	}
+	type response struct {
+		Params []*internal.Param `json:"params"`
+	}
+	res := &response{Params: pp}
+	if err := json.NewEncoder(w).Encode(res); err != nil {
 		log.Error(err)
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
-	withAllParams := req.URL.Query().Get("all_params")
-	wAll, err := strconv.ParseBool(withAllParams)
+	return
+}
+
+func (r *Runner) GetParamClasses(w http.ResponseWriter, req *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(req, "id"))
 	if err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	cc, err := r.do.ReadParamClasses(id)
+	if err != nil {
+		log.Error(err)
+		writeError(w, err)
+		return
+	}
+	type response struct {
+		Classes []*internal.Class `json:"classes"`
+	}
+	res := &response{Classes: cc}
+	if err := json.NewEncoder(w).Encode(res); err != nil {
 		log.Error(err)
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	return
+}
+
+func (r *Runner) AddC(w http.ResponseWriter, req *http.Request) {
+	type request struct {
+		Classes []*internal.Class `json:"classes"`
+	}
+	re := &request{}
+	if err := json.NewDecoder(req.Body).Decode(&re); err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	partial, err := readMode(req)
+	if err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	if partial {
+		writeItemResults(w, r.do.CreateClassesPartially(re.Classes))
+		return
+	}
+	if err := r.do.CreateClasses(re.Classes); err != nil {
+		log.Error(err)
+		writeError(w, err)
+		return
+	}
+	return
+}
+
+func (r *Runner) GetC(w http.ResponseWriter, req *http.Request) {
+	id, err := readId(req, "class_id")
+	if err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	// all_params is only optional on the resource route, the legacy route keeps requiring it
+	var wAll bool
+	withAllParams := req.URL.Query().Get("all_params")
+	if withAllParams != "" || chi.URLParam(req, "id") == "" {
+		if wAll, err = strconv.ParseBool(withAllParams); err != nil {
+			log.Error(err)
+			writeError(w, newRequestErr(err))
+			return
+		}
+	}
 	c, err := r.do.ReadClass(id, wAll)
 	if err != nil {
 		log.Error(err)
-		w.WriteHeader(http.StatusBadRequest)
+		writeError(w, err)
 		return
 	}
+	setETag(w, c.Version)
 	type response struct {
 		Class *internal.Class `json:"class"`
 	}
@@ -199,7 +583,7 @@ func (r *Runner) GetCTree(w http.ResponseWriter, req *http.Request) {
 	cc, err := r.do.ReadClassTree()
 	if err != nil {
 		log.Error(err)
-		w.WriteHeader(http.StatusBadRequest)
+		writeError(w, err)
 		return
 	}
 	type response struct {
@@ -215,11 +599,22 @@ func (r *Runner) GetCTree(w http.ResponseWriter, req *http.Request) {
 }
 
 func (r *Runner) GetCChildren(w http.ResponseWriter, req *http.Request) {
-	nameClass := req.URL.Query().Get("class_name")
-	c, err := r.do.ReadClassChildren(nameClass)
+	var c *internal.Class
+	var err error
+	if chi.URLParam(req, "id") != "" {
+		var id int
+		if id, err = readId(req, ""); err != nil {
+			log.Error(err)
+			writeError(w, newRequestErr(err))
+			return
+		}
+		c, err = r.do.ReadClassSubtree(id)
+	} else {
+		c, err = r.do.ReadClassChildren(req.URL.Query().Get("class_name"))
+	}
 	if err != nil {
 		log.Error(err)
-		w.WriteHeader(http.StatusBadRequest)
+		writeError(w, err)
 		return
 	}
 	type response struct {
@@ -235,16 +630,70 @@ func (r *Runner) GetCChildren(w http.ResponseWriter, req *http.Request) {
 }
 
 func (r *Runner) DeleteC(w http.ResponseWriter, req *http.Request) {
-	idClass := req.URL.Query().Get("class_id")
-	id, err := strconv.Atoi(idClass)
+	id, err := readId(req, "class_id")
 	if err != nil {
 		log.Error(err)
-		w.WriteHeader(http.StatusBadRequest)
+		writeError(w, newRequestErr(err))
 		return
 	}
-	if err := r.do.DeleteClass(id); err != nil {
+	if err := r.do.DeleteClass(id, readIfMatch(req)); err != nil {
+		log.Error(err)
+		writeError(w, err)
+		return
+	}
+	return
+}
+
+func (r *Runner) UpdateC(w http.ResponseWriter, req *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(req, "id"))
+	if err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	re := &internal.ClassUpdate{}
+	if err := json.NewDecoder(req.Body).Decode(&re); err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	if err := r.do.UpdateClass(id, re, readIfMatch(req)); err != nil {
+		log.Error(err)
+		writeError(w, err)
+		return
+	}
+	return
+}
+
+func (r *Runner) MoveC(w http.ResponseWriter, req *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(req, "id"))
+	if err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	type request struct {
+		// ParentId is zero to make the class a root
+		ParentId int  `json:"parent_id"`
+		Force    bool `json:"force"`
+	}
+	re := &request{}
+	if err := json.NewDecoder(req.Body).Decode(&re); err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	report, err := r.do.MoveClass(id, re.ParentId, re.Force)
+	if err != nil {
+		log.Error(err)
+		writeError(w, err)
+		return
+	}
+	if !report.Moved {
+		w.WriteHeader(http.StatusConflict)
+	}
+	if err := json.NewEncoder(w).Encode(report); err != nil {
 		log.Error(err)
-		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 	return
@@ -257,31 +706,41 @@ func (r *Runner) AddP(w http.ResponseWriter, req *http.Request) {
 	re := &request{}
 	if err := json.NewDecoder(req.Body).Decode(&re); err != nil {
 		log.Error(err)
-		w.WriteHeader(http.StatusBadRequest)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	partial, err := readMode(req)
+	if err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	if partial {
+		writeItemResults(w, r.do.CreateProductsPartially(re.Products))
 		return
 	}
 	if err := r.do.CreateProducts(re.Products); err != nil {
 		log.Error(err)
-		w.WriteHeader(http.StatusBadRequest)
+		writeError(w, err)
 		return
 	}
 	return
 }
 
 func (r *Runner) GetP(w http.ResponseWriter, req *http.Request) {
-	idClass := req.URL.Query().Get("product_id")
-	id, err := strconv.Atoi(idClass)
+	id, err := readId(req, "product_id")
 	if err != nil {
 		log.Error(err)
-		w.WriteHeader(http.StatusBadRequest)
+		writeError(w, newRequestErr(err))
 		return
 	}
-	p, err := r.do.ReadProduct(id)
+	p, err := r.do.ReadProduct(id, req.URL.Query().Get("unit"))
 	if err != nil {
 		log.Error(err)
-		w.WriteHeader(http.StatusBadRequest)
+		writeError(w, err)
 		return
 	}
+	setETag(w, p.Version)
 	type response struct {
 		Product *internal.Product `json:"product"`
 	}
@@ -295,23 +754,31 @@ func (r *Runner) GetP(w http.ResponseWriter, req *http.Request) {
 }
 
 func (r *Runner) GetPC(w http.ResponseWriter, req *http.Request) {
-	idClass := req.URL.Query().Get("class_id")
-	id, err := strconv.Atoi(idClass)
+	id, err := readId(req, "class_id")
 	if err != nil {
 		log.Error(err)
-		w.WriteHeader(http.StatusBadRequest)
+		writeError(w, newRequestErr(err))
 		return
 	}
-	pp, err := r.do.ReadClassProducts(id)
+	page, _, err := readPage(req)
 	if err != nil {
 		log.Error(err)
-		w.WriteHeader(http.StatusBadRequest)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	pp, total, err := r.do.ReadClassProducts(id, req.URL.Query().Get("unit"), page)
+	if err != nil {
+		log.Error(err)
+		writeError(w, err)
 		return
 	}
 	type response struct {
 		Products []*internal.Product `json:"products"`
+		Total    int                 `json:"total"`
+		Limit    int                 `json:"limit"`
+		Offset   int                 `json:"offset"`
 	}
-	res := &response{Products:pp}
+	res := &response{Products: pp, Total: total, Limit: page.Limit, Offset: page.Offset}
 	if err := json.NewEncoder(w).Encode(res); err != nil {
 		log.Error(err)
 		w.WriteHeader(http.StatusBadRequest)
@@ -327,29 +794,178 @@ func (r *Runner) UpdateP(w http.ResponseWriter, req *http.Request) {
 	re := &request{}
 	if err := json.NewDecoder(req.Body).Decode(&re); err != nil {
 		log.Error(err)
-		w.WriteHeader(http.StatusBadRequest)
+		writeError(w, newRequestErr(err))
 		return
 	}
-	if err := r.do.UpdateProduct(re.Product); err != nil {
+	if chi.URLParam(req, "id") != "" {
+		id, err := readId(req, "")
+		if err != nil {
+			log.Error(err)
+			writeError(w, newRequestErr(err))
+			return
+		}
+		re.Product.Id = id
+	}
+	if err := r.do.UpdateProduct(re.Product, readIfMatch(req)); err != nil {
 		log.Error(err)
-		w.WriteHeader(http.StatusBadRequest)
+		writeError(w, err)
+		return
+	}
+	return
+}
+
+func (r *Runner) PatchP(w http.ResponseWriter, req *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(req, "id"))
+	if err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	// only the name and the listed params are changed, a null value removes the value
+	re := &internal.Product{}
+	if err := json.NewDecoder(req.Body).Decode(&re); err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	if err := r.do.PatchProduct(id, re, readIfMatch(req)); err != nil {
+		log.Error(err)
+		writeError(w, err)
 		return
 	}
 	return
 }
 
 func (r *Runner) DeletePC(w http.ResponseWriter, req *http.Request) {
-	idClass := req.URL.Query().Get("product_id")
-	id, err := strconv.Atoi(idClass)
+	id, err := readId(req, "product_id")
+	if err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	if err := r.do.DeleteProduct(id, readIfMatch(req)); err != nil {
+		log.Error(err)
+		writeError(w, err)
+		return
+	}
+	return
+}
+
+// SearchP takes filters in the param:op:value form, the in operator takes comma separated values
+func (r *Runner) SearchP(w http.ResponseWriter, req *http.Request) {
+	q := req.URL.Query()
+	id, err := readId(req, "class_id")
+	if err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	var preds []*database.Predicate
+	for _, filter := range q["filter"] {
+		pred, err := readPredicate(filter)
+		if err != nil {
+			log.Error(err)
+			writeError(w, newRequestErr(err))
+			return
+		}
+		preds = append(preds, pred)
+	}
+	var facets []string
+	if f := q.Get("facets"); f != "" {
+		facets = strings.Split(f, ",")
+	}
+	page, _, err := readPage(req)
 	if err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	pp, total, ff, err := r.do.SearchProducts(id, preds, facets, page)
+	if err != nil {
+		log.Error(err)
+		writeError(w, err)
+		return
+	}
+	type response struct {
+		Products []*internal.Product `json:"products"`
+		Total    int                 `json:"total"`
+		Limit    int                 `json:"limit"`
+		Offset   int                 `json:"offset"`
+		Facets   []*internal.Facet   `json:"facets"`
+	}
+	res := &response{Products: pp, Total: total, Limit: page.Limit, Offset: page.Offset, Facets: ff}
+	if err := json.NewEncoder(w).Encode(res); err != nil {
 		log.Error(err)
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
-	if err := r.do.DeleteProduct(id); err != nil {
+	return
+}
+
+var predicateOps = []string{database.OpEq, database.OpNe, database.OpGt, database.OpGte,
+	database.OpLt, database.OpLte, database.OpIn}
+
+// readPredicate splits the filter param:op:value at the first known operator,
+// so the value may have colons of its own like ratio:eq:16:9
+func readPredicate(filter string) (*database.Predicate, error) {
+	for i := strings.Index(filter, ":"); i >= 0; {
+		for _, op := range predicateOps {
+			if !strings.HasPrefix(filter[i+1:], op+":") {
+				continue
+			}
+			value := filter[i+len(op)+2:]
+			pred := &database.Predicate{
+				Param:  filter[:i],
+				Op:     op,
+				Values: []string{value},
+			}
+			if pred.Op == database.OpIn {
+				pred.Values = strings.Split(value, ",")
+			}
+			return pred, nil
+		}
+		next := strings.Index(filter[i+1:], ":")
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return nil, fmt.Errorf("invalid filter %s, expected param:op:value with op one of %s",
+		filter, strings.Join(predicateOps, ", "))
+}
+
+func (r *Runner) Search(w http.ResponseWriter, req *http.Request) {
+	text := req.URL.Query().Get("q")
+	if text == "" {
+		err := errors.New("empty search")
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	limit := 20
+	if l := req.URL.Query().Get("limit"); l != "" {
+		var err error
+		if limit, err = strconv.Atoi(l); err != nil || limit <= 0 {
+			err = fmt.Errorf("invalid limit %s", l)
+			log.Error(err)
+			writeError(w, newRequestErr(err))
+			return
+		}
+	}
+	hits, err := r.do.Search(text, limit)
+	if err != nil {
+		log.Error(err)
+		writeError(w, err)
+		return
+	}
+	type response struct {
+		Hits []*internal.SearchHit `json:"hits"`
+	}
+	res := &response{Hits: hits}
+	if err := json.NewEncoder(w).Encode(res); err != nil {
 		log.Error(err)
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 	return
-}
\ No newline at end of file
+}
@@ -0,0 +1,40 @@
+package runner
+
+import (
+	"hseSQL/internal/database"
+	"reflect"
+	"testing"
+)
+
+func TestReadPredicate(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   *database.Predicate
+	}{
+		{"color:eq:red", &database.Predicate{Param: "color", Op: database.OpEq, Values: []string{"red"}}},
+		{"ratio:eq:16:9", &database.Predicate{Param: "ratio", Op: database.OpEq, Values: []string{"16:9"}}},
+		{"time:gte:12:30:00", &database.Predicate{Param: "time", Op: database.OpGte, Values: []string{"12:30:00"}}},
+		{"size:in:s,m,l", &database.Predicate{Param: "size", Op: database.OpIn, Values: []string{"s", "m", "l"}}},
+		{"a:b:lt:5", &database.Predicate{Param: "a:b", Op: database.OpLt, Values: []string{"5"}}},
+		{"name:eq:", &database.Predicate{Param: "name", Op: database.OpEq, Values: []string{""}}},
+		{"color", nil},
+		{"color:red", nil},
+		{"color:like:red", nil},
+	}
+	for _, tt := range tests {
+		got, err := readPredicate(tt.filter)
+		if tt.want == nil {
+			if err == nil {
+				t.Errorf("readPredicate(%q) = %+v, want an error", tt.filter, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("readPredicate(%q): %v", tt.filter, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("readPredicate(%q) = %+v, want %+v", tt.filter, got, tt.want)
+		}
+	}
+}
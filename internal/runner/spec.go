@@ -0,0 +1,938 @@
+package runner
+
+import (
+	"hseSQL/internal"
+	"hseSQL/internal/database"
+	"hseSQL/internal/openapi"
+	"hseSQL/internal/xlsx"
+)
+
+func ref(name string) *openapi.Schema {
+	return &openapi.Schema{Ref: "#/components/schemas/" + name}
+}
+
+func schemaOf(typ string) *openapi.Schema {
+	return &openapi.Schema{Type: typ}
+}
+
+func arrayOf(items *openapi.Schema) *openapi.Schema {
+	return &openapi.Schema{Type: "array", Items: items, Nullable: true}
+}
+
+func object(props map[string]*openapi.Schema, required ...string) *openapi.Schema {
+	return &openapi.Schema{Type: "object", Properties: props, Required: required}
+}
+
+func nullable(s *openapi.Schema) *openapi.Schema {
+	if s.Ref != "" {
+		return &openapi.Schema{OneOf: []*openapi.Schema{s}, Nullable: true}
+	}
+	s.Nullable = true
+	return s
+}
+
+func nonNegative(typ string) *openapi.Schema {
+	zero := 0.0
+	return &openapi.Schema{Type: typ, Minimum: &zero}
+}
+
+func jsonBody(s *openapi.Schema) *openapi.RequestBody {
+	return &openapi.RequestBody{
+		Required: true,
+		Content:  map[string]*openapi.MediaType{"application/json": {Schema: s}},
+	}
+}
+
+func jsonResponse(description string, s *openapi.Schema) *openapi.Response {
+	return &openapi.Response{
+		Description: description,
+		Content:     map[string]*openapi.MediaType{"application/json": {Schema: s}},
+	}
+}
+
+// responses adds the error response every operation has, a nil schema means an empty body
+func responses(description string, s *openapi.Schema) map[string]*openapi.Response {
+	ok := &openapi.Response{Description: description}
+	if s != nil {
+		ok = jsonResponse(description, s)
+	}
+	return map[string]*openapi.Response{
+		"200":     ok,
+		"default": errorResponse(),
+	}
+}
+
+func errorResponse() *openapi.Response {
+	return jsonResponse("error", object(map[string]*openapi.Schema{"error": ref("Error")}, "error"))
+}
+
+func query(name, description string, s *openapi.Schema, required bool) *openapi.Parameter {
+	return &openapi.Parameter{Name: name, In: "query", Description: description, Schema: s, Required: required}
+}
+
+func pathId(description string) *openapi.Parameter {
+	return &openapi.Parameter{Name: "id", In: "path", Description: description, Schema: schemaOf("integer"), Required: true}
+}
+
+func pageParams(sortKeys string) []*openapi.Parameter {
+	return []*openapi.Parameter{
+		query("limit", "max number of items, all the rest if not set", nonNegative("integer"), false),
+		query("offset", "number of items to skip", nonNegative("integer"), false),
+		query("sort", "sort key prefixed with - for the descending order: "+sortKeys, schemaOf("string"), false),
+	}
+}
+
+func pageOf(name string, items *openapi.Schema) *openapi.Schema {
+	return object(map[string]*openapi.Schema{
+		name:     arrayOf(items),
+		"total":  schemaOf("integer"),
+		"limit":  schemaOf("integer"),
+		"offset": schemaOf("integer"),
+	})
+}
+
+func idsResponse(name string) map[string]*openapi.Response {
+	return responses("ids of the created items", object(map[string]*openapi.Schema{name: arrayOf(schemaOf("integer"))}))
+}
+
+// ifMatch is the version the client has read, a change of an outdated version fails with 412
+func ifMatch() *openapi.Parameter {
+	return &openapi.Parameter{Name: "If-Match", In: "header", Description: "ETag of the read version", Schema: schemaOf("string")}
+}
+
+// withETag adds the ETag header with the version of the read item to the successful response
+func withETag(resps map[string]*openapi.Response) map[string]*openapi.Response {
+	resps["200"].Headers = map[string]*openapi.Header{
+		"ETag": {Description: "version of the item for If-Match", Schema: schemaOf("string")},
+	}
+	return resps
+}
+
+// modeParam selects how the bulk create routes deal with the rejected items
+func modeParam() *openapi.Parameter {
+	return query("mode", "partial creates every item on its own and responds with the result of each item",
+		&openapi.Schema{Type: "string", Enum: []string{modeAtomic, modePartial}}, false)
+}
+
+// bulkResponses adds the per-item results of the partial mode to the response of the atomic one,
+// a nil schema means the atomic mode responds with an empty body
+func bulkResponses(description string, s *openapi.Schema) map[string]*openapi.Response {
+	if s == nil {
+		return responses(description+", the results of the items in the partial mode", ref("ItemResults"))
+	}
+	return responses(description, &openapi.Schema{OneOf: []*openapi.Schema{s, ref("ItemResults")}})
+}
+
+// apiSpec describes every route of the router, the legacy routes are marked as deprecated
+func apiSpec() *openapi.Document {
+	anyValue := &openapi.Schema{Description: "a value of the base type of the param"}
+	paths := legacyPaths()
+	for _, item := range paths {
+		for _, op := range *item {
+			op.Deprecated = true
+		}
+	}
+	for path, item := range v1Paths() {
+		paths[apiV1+path] = item
+	}
+	for path, item := range adminPaths() {
+		paths[path] = item
+	}
+	return &openapi.Document{
+		OpenAPI: openapi.Version,
+		Info: &openapi.Info{
+			Title:       "hseSQL",
+			Description: "Catalog of product classes, their params and products",
+			Version:     "1.0",
+		},
+		Paths: paths,
+		Components: &openapi.Components{
+			Schemas: map[string]*openapi.Schema{
+				"EI": object(map[string]*openapi.Schema{
+					"id":         schemaOf("integer"),
+					"name":       schemaOf("string"),
+					"short_name": schemaOf("string"),
+					"dimension":  schemaOf("string"),
+					"factor":     schemaOf("number"),
+					"offset":     schemaOf("number"),
+				}, "name"),
+				"Dimension": object(map[string]*openapi.Schema{
+					"id":    schemaOf("integer"),
+					"name":  schemaOf("string"),
+					"units": arrayOf(ref("EI")),
+				}),
+				"ValueType": {
+					Description: "a bare name stands for a string value type",
+					OneOf: []*openapi.Schema{
+						schemaOf("string"),
+						object(map[string]*openapi.Schema{
+							"id":   schemaOf("integer"),
+							"name": schemaOf("string"),
+							"base_type": {Type: "string", Enum: []string{
+								internal.BaseTypeInteger, internal.BaseTypeDecimal, internal.BaseTypeBoolean,
+								internal.BaseTypeDate, internal.BaseTypeString, internal.BaseTypeEnum,
+							}},
+							"values": arrayOf(ref("EnumValue")),
+						}, "name"),
+					},
+				},
+				"EnumValue": {
+					Description: "a bare string stands for the value",
+					OneOf: []*openapi.Schema{
+						schemaOf("string"),
+						object(map[string]*openapi.Schema{
+							"id":      schemaOf("integer"),
+							"value":   schemaOf("string"),
+							"retired": schemaOf("boolean"),
+						}, "value"),
+					},
+				},
+				"ParamConstraints": object(map[string]*openapi.Schema{
+					"min":        schemaOf("number"),
+					"max":        schemaOf("number"),
+					"step":       schemaOf("number"),
+					"precision":  schemaOf("integer"),
+					"max_length": schemaOf("integer"),
+				}),
+				"Param": object(map[string]*openapi.Schema{
+					"id":          schemaOf("integer"),
+					"name":        schemaOf("string"),
+					"val_type":    schemaOf("string"),
+					"base_type":   schemaOf("string"),
+					"ei":          nullable(ref("EI")),
+					"constraints": nullable(ref("ParamConstraints")),
+					"required":    schemaOf("boolean"),
+					"default":     anyValue,
+					"display_ei":  nullable(ref("EI")),
+				}, "name"),
+				"ParamOverride": object(map[string]*openapi.Schema{
+					"name":        schemaOf("string"),
+					"constraints": nullable(ref("ParamConstraints")),
+					"required":    nullable(schemaOf("boolean")),
+					"default":     anyValue,
+					"display_ei":  nullable(ref("EI")),
+				}, "name"),
+				"ParamAndValues": object(map[string]*openapi.Schema{
+					"param": ref("Param"),
+					"value": anyValue,
+					"unit":  schemaOf("string"),
+				}, "param"),
+				"Class": object(map[string]*openapi.Schema{
+					"id":        schemaOf("integer"),
+					"name":      schemaOf("string"),
+					"version":   schemaOf("integer"),
+					"children":  arrayOf(ref("Class")),
+					"ei":        nullable(ref("EI")),
+					"params":    arrayOf(ref("Param")),
+					"overrides": arrayOf(ref("ParamOverride")),
+				}),
+				"ClassUpdate": object(map[string]*openapi.Schema{
+					"name":             schemaOf("string"),
+					"ei":               nullable(ref("EI")),
+					"add_params":       arrayOf(ref("Param")),
+					"remove_params":    arrayOf(schemaOf("string")),
+					"set_overrides":    arrayOf(ref("ParamOverride")),
+					"remove_overrides": arrayOf(schemaOf("string")),
+					"drop_values":      schemaOf("boolean"),
+				}),
+				"Product": object(map[string]*openapi.Schema{
+					"id":           schemaOf("integer"),
+					"name":         schemaOf("string"),
+					"version":      schemaOf("integer"),
+					"parent_class": nullable(ref("Class")),
+					"params":       arrayOf(ref("ParamAndValues")),
+				}),
+				"Facet": object(map[string]*openapi.Schema{
+					"param":  schemaOf("string"),
+					"values": arrayOf(ref("FacetValue")),
+				}),
+				"FacetValue": object(map[string]*openapi.Schema{
+					"value": schemaOf("string"),
+					"count": schemaOf("integer"),
+				}),
+				"SearchHit": object(map[string]*openapi.Schema{
+					"kind":       {Type: "string", Enum: []string{"class", "product", "param", "value"}},
+					"id":         schemaOf("integer"),
+					"name":       schemaOf("string"),
+					"param":      schemaOf("string"),
+					"text":       schemaOf("string"),
+					"highlights": arrayOf(ref("TextRange")),
+					"rank":       schemaOf("number"),
+				}),
+				"TextRange": object(map[string]*openapi.Schema{
+					"start": schemaOf("integer"),
+					"end":   schemaOf("integer"),
+				}),
+				"FieldError": object(map[string]*openapi.Schema{
+					"product": schemaOf("string"),
+					"param":   schemaOf("string"),
+					"message": schemaOf("string"),
+				}),
+				"ItemResults": object(map[string]*openapi.Schema{
+					"results": arrayOf(ref("ItemResult")),
+					"created": schemaOf("integer"),
+					"failed":  schemaOf("integer"),
+				}),
+				"ItemResult": object(map[string]*openapi.Schema{
+					"index": schemaOf("integer"),
+					"id":    schemaOf("integer"),
+					"error": ref("Error"),
+				}, "index"),
+				"ImportReport": object(map[string]*openapi.Schema{
+					"imported": schemaOf("boolean"),
+					"created":  schemaOf("integer"),
+					"failed":   schemaOf("integer"),
+					"rows":     arrayOf(ref("ImportRow")),
+				}),
+				"ImportRow": object(map[string]*openapi.Schema{
+					"row":    schemaOf("integer"),
+					"name":   schemaOf("string"),
+					"id":     schemaOf("integer"),
+					"errors": arrayOf(ref("FieldError")),
+				}),
+				"Dump": object(map[string]*openapi.Schema{
+					"format":      {Type: "string", Enum: []string{database.DumpFormat}},
+					"version":     schemaOf("integer"),
+					"created":     {Type: "string", Format: "date-time"},
+					"dimensions":  arrayOf(schemaOf("string")),
+					"eis":         arrayOf(ref("EI")),
+					"value_types": arrayOf(ref("ValueType")),
+					"params":      arrayOf(ref("Param")),
+					"classes":     arrayOf(ref("Class")),
+					"products":    arrayOf(ref("DumpProduct")),
+				}, "format", "version"),
+				"DumpProduct": object(map[string]*openapi.Schema{
+					"id":     schemaOf("integer"),
+					"name":   schemaOf("string"),
+					"class":  schemaOf("string"),
+					"values": {Type: "object", Description: "values by the param names in the units of the params"},
+				}, "name", "class"),
+				"RestoreReport": object(map[string]*openapi.Schema{
+					"ids":     {Type: "object", Description: "ids of the dump mapped to the ids in the database by the kind of the items"},
+					"created": {Type: "object", Description: "number of the created items by the kind"},
+					"reused":  {Type: "object", Description: "number of the items found by the name by the kind"},
+				}),
+				"ClassificationReport": object(map[string]*openapi.Schema{
+					"system":   schemaOf("string"),
+					"release":  schemaOf("string"),
+					"created":  {Type: "object", Description: "number of the created items by the kind"},
+					"updated":  {Type: "object", Description: "number of the changed items by the kind"},
+					"warnings": arrayOf(schemaOf("string")),
+				}),
+				"MoveReport": object(map[string]*openapi.Schema{
+					"moved":     schemaOf("boolean"),
+					"conflicts": arrayOf(ref("FieldError")),
+				}),
+				"Error": object(map[string]*openapi.Schema{
+					"code": {Type: "string", Enum: []string{
+						codeBadRequest, codeNotFound, codeConflict, codeInvalid, codeValidationFailed, codePreconditionFailed, codeInternal,
+					}},
+					"message": schemaOf("string"),
+					"details": {Type: "array", Items: &openapi.Schema{}, Description: "rejected values or request fields"},
+				}, "code", "message"),
+			},
+		},
+	}
+}
+
+// legacyPaths describes the routes kept for the clients of the api before /api/v1
+func legacyPaths() map[string]*openapi.PathItem {
+	unitParam := query("unit", "unit to convert the numeric values to", schemaOf("string"), false)
+	return map[string]*openapi.PathItem{
+		"/ei": {
+			"post": {
+				Summary:     "Create units",
+				Tags:        []string{"units"},
+				Parameters:  []*openapi.Parameter{modeParam()},
+				RequestBody: jsonBody(arrayOf(ref("EI"))),
+				Responses:   bulkResponses("ids of the units", object(map[string]*openapi.Schema{"Ids": arrayOf(schemaOf("integer"))})),
+			},
+			"get": {
+				Summary:     "List units",
+				Description: "Without paging params the plain list of units is returned",
+				Tags:        []string{"units"},
+				Parameters: append([]*openapi.Parameter{
+					query("ei_name", "filter by the unit name", schemaOf("string"), false),
+				}, pageParams("id, name")...),
+				Responses: responses("units", &openapi.Schema{OneOf: []*openapi.Schema{
+					arrayOf(ref("EI")),
+					pageOf("eis", ref("EI")),
+				}}),
+			},
+		},
+		"/dimension": {
+			"post": {
+				Summary:     "Create dimensions",
+				Tags:        []string{"units"},
+				RequestBody: jsonBody(object(map[string]*openapi.Schema{"dimensions": arrayOf(schemaOf("string"))})),
+				Responses:   idsResponse("ids"),
+			},
+			"get": {
+				Summary:   "List dimensions with their units",
+				Tags:      []string{"units"},
+				Responses: responses("dimensions", object(map[string]*openapi.Schema{"dimensions": arrayOf(ref("Dimension"))})),
+			},
+		},
+		"/valuetype": {
+			"post": {
+				Summary:     "Create value types",
+				Tags:        []string{"value types"},
+				RequestBody: jsonBody(object(map[string]*openapi.Schema{"value_types": arrayOf(ref("ValueType"))})),
+				Responses:   responses("created", nil),
+			},
+			"get": {
+				Summary:    "List value types",
+				Tags:       []string{"value types"},
+				Parameters: pageParams("id, name"),
+				Responses:  responses("value types", pageOf("value_types", ref("ValueType"))),
+			},
+		},
+		"/valuetype/values": {
+			"post": {
+				Summary: "Add values to an enum value type",
+				Tags:    []string{"value types"},
+				RequestBody: jsonBody(object(map[string]*openapi.Schema{
+					"value_type": schemaOf("string"),
+					"values":     arrayOf(schemaOf("string")),
+				}, "value_type", "values")),
+				Responses: responses("added", nil),
+			},
+			"put": {
+				Summary: "Rename a value of an enum value type",
+				Tags:    []string{"value types"},
+				RequestBody: jsonBody(object(map[string]*openapi.Schema{
+					"value_type": schemaOf("string"),
+					"value":      schemaOf("string"),
+					"new_value":  schemaOf("string"),
+				}, "value_type", "value", "new_value")),
+				Responses: responses("renamed", nil),
+			},
+			"delete": {
+				Summary: "Retire a value of an enum value type",
+				Tags:    []string{"value types"},
+				Parameters: []*openapi.Parameter{
+					query("value_type", "", schemaOf("string"), true),
+					query("value", "", schemaOf("string"), true),
+				},
+				Responses: responses("retired", nil),
+			},
+		},
+		"/param": {
+			"post": {
+				Summary:     "Create params of the dictionary",
+				Tags:        []string{"params"},
+				RequestBody: jsonBody(object(map[string]*openapi.Schema{"params": arrayOf(ref("Param"))})),
+				Responses:   idsResponse("ids"),
+			},
+			"get": {
+				Summary:    "List params of the dictionary",
+				Tags:       []string{"params"},
+				Parameters: []*openapi.Parameter{query("param_name", "filter by the param name", schemaOf("string"), false)},
+				Responses:  responses("params", object(map[string]*openapi.Schema{"params": arrayOf(ref("Param"))})),
+			},
+		},
+		"/param/{id}/classes": {
+			"get": {
+				Summary:    "List classes that use the param",
+				Tags:       []string{"params"},
+				Parameters: []*openapi.Parameter{pathId("param id")},
+				Responses:  responses("classes", object(map[string]*openapi.Schema{"classes": arrayOf(ref("Class"))})),
+			},
+		},
+		"/class": {
+			"post": {
+				Summary:     "Create classes",
+				Tags:        []string{"classes"},
+				Parameters:  []*openapi.Parameter{modeParam()},
+				RequestBody: jsonBody(object(map[string]*openapi.Schema{"classes": arrayOf(ref("Class"))})),
+				Responses:   bulkResponses("created", nil),
+			},
+			"get": {
+				Summary: "Read a class",
+				Tags:    []string{"classes"},
+				Parameters: []*openapi.Parameter{
+					query("class_id", "", schemaOf("integer"), true),
+					query("all_params", "include the params inherited from the ancestors", schemaOf("boolean"), true),
+				},
+				Responses: withETag(responses("class", object(map[string]*openapi.Schema{"class": ref("Class")}))),
+			},
+			"delete": {
+				Summary:    "Delete a class",
+				Tags:       []string{"classes"},
+				Parameters: []*openapi.Parameter{query("class_id", "", schemaOf("integer"), true), ifMatch()},
+				Responses:  responses("deleted", nil),
+			},
+		},
+		"/classtree": {
+			"get": {
+				Summary:   "Read the whole class tree",
+				Tags:      []string{"classes"},
+				Responses: responses("root classes", object(map[string]*openapi.Schema{"classes": arrayOf(ref("Class"))})),
+			},
+		},
+		"/classchildren": {
+			"get": {
+				Summary:    "Read a class with its subtree",
+				Tags:       []string{"classes"},
+				Parameters: []*openapi.Parameter{query("class_name", "", schemaOf("string"), true)},
+				Responses:  responses("class", object(map[string]*openapi.Schema{"class": ref("Class")})),
+			},
+		},
+		"/class/{id}": {
+			"put": {
+				Summary:     "Update a class in place",
+				Tags:        []string{"classes"},
+				Parameters:  []*openapi.Parameter{pathId("class id"), ifMatch()},
+				RequestBody: jsonBody(ref("ClassUpdate")),
+				Responses:   responses("updated", nil),
+			},
+		},
+		"/class/{id}/move": {
+			"post": {
+				Summary:     "Move a class under another parent",
+				Description: "Responds with 409 and the conflicting product values unless force is set, which drops them. A required param can't be left without a value",
+				Tags:        []string{"classes"},
+				Parameters:  []*openapi.Parameter{pathId("class id")},
+				RequestBody: jsonBody(object(map[string]*openapi.Schema{
+					"parent_id": nonNegative("integer"),
+					"force":     schemaOf("boolean"),
+				})),
+				Responses: responses("move report", ref("MoveReport")),
+			},
+		},
+		"/product": {
+			"post": {
+				Summary:     "Create products",
+				Tags:        []string{"products"},
+				Parameters:  []*openapi.Parameter{modeParam()},
+				RequestBody: jsonBody(object(map[string]*openapi.Schema{"products": arrayOf(ref("Product"))})),
+				Responses:   bulkResponses("created", nil),
+			},
+			"get": {
+				Summary: "Read a product",
+				Tags:    []string{"products"},
+				Parameters: []*openapi.Parameter{
+					query("product_id", "", schemaOf("integer"), true),
+					unitParam,
+				},
+				Responses: withETag(responses("product", object(map[string]*openapi.Schema{"product": ref("Product")}))),
+			},
+			"put": {
+				Summary:     "Replace a product",
+				Tags:        []string{"products"},
+				Parameters:  []*openapi.Parameter{ifMatch()},
+				RequestBody: jsonBody(object(map[string]*openapi.Schema{"product": ref("Product")}, "product")),
+				Responses:   responses("updated", nil),
+			},
+			"delete": {
+				Summary:    "Delete a product",
+				Tags:       []string{"products"},
+				Parameters: []*openapi.Parameter{query("product_id", "", schemaOf("integer"), true), ifMatch()},
+				Responses:  responses("deleted", nil),
+			},
+		},
+		"/product/{id}": {
+			"patch": {
+				Summary:     "Change the name and the listed params of a product",
+				Description: "A null value removes the value of the param",
+				Tags:        []string{"products"},
+				Parameters:  []*openapi.Parameter{pathId("product id"), ifMatch()},
+				RequestBody: jsonBody(ref("Product")),
+				Responses:   responses("updated", nil),
+			},
+		},
+		"/productclass": {
+			"get": {
+				Summary: "List products of a class",
+				Tags:    []string{"products"},
+				Parameters: append([]*openapi.Parameter{
+					query("class_id", "", schemaOf("integer"), true),
+					unitParam,
+				}, pageParams("id, name, param:<param name>")...),
+				Responses: responses("products", pageOf("products", ref("Product"))),
+			},
+		},
+		"/products/search": {
+			"get": {
+				Summary: "Search products of a class subtree by param values",
+				Tags:    []string{"products"},
+				Parameters: append([]*openapi.Parameter{
+					query("class_id", "", schemaOf("integer"), true),
+					query("filter", "param:op:value, op is one of eq, ne, gt, gte, lt, lte, in; "+
+						"in takes comma separated values, the value may have colons", arrayOf(schemaOf("string")), false),
+					query("facets", "comma separated params to count the values of", schemaOf("string"), false),
+				}, pageParams("id, name")...),
+				Responses: responses("products and facets", object(map[string]*openapi.Schema{
+					"products": arrayOf(ref("Product")),
+					"total":    schemaOf("integer"),
+					"limit":    schemaOf("integer"),
+					"offset":   schemaOf("integer"),
+					"facets":   arrayOf(ref("Facet")),
+				})),
+			},
+		},
+		"/search": {
+			"get": {
+				Summary: "Full text search over class, product and param names and string and enum values",
+				Tags:    []string{"search"},
+				Parameters: []*openapi.Parameter{
+					query("q", "text to look for", schemaOf("string"), true),
+					query("limit", "max number of hits, 20 by default", nonNegative("integer"), false),
+				},
+				Responses: responses("hits", object(map[string]*openapi.Schema{"hits": arrayOf(ref("SearchHit"))})),
+			},
+		},
+	}
+}
+
+// v1Paths describes the resource routes under /api/v1, the bodies are the same as the ones of the legacy routes
+func v1Paths() map[string]*openapi.PathItem {
+	unitParam := query("unit", "unit to convert the numeric values to", schemaOf("string"), false)
+	classId := pathId("class id")
+	productId := pathId("product id")
+	valueTypeName := &openapi.Parameter{Name: "name", In: "path", Description: "value type name", Schema: schemaOf("string"), Required: true}
+	enumValue := &openapi.Parameter{Name: "value", In: "path", Description: "enum value", Schema: schemaOf("string"), Required: true}
+	return map[string]*openapi.PathItem{
+		"/units": {
+			"post": {
+				Summary:     "Create units",
+				Tags:        []string{"units"},
+				Parameters:  []*openapi.Parameter{modeParam()},
+				RequestBody: jsonBody(arrayOf(ref("EI"))),
+				Responses:   bulkResponses("ids of the units", object(map[string]*openapi.Schema{"Ids": arrayOf(schemaOf("integer"))})),
+			},
+			"get": {
+				Summary: "List units",
+				Tags:    []string{"units"},
+				Parameters: append([]*openapi.Parameter{
+					query("ei_name", "filter by the unit name", schemaOf("string"), false),
+				}, pageParams("id, name")...),
+				Responses: responses("units", pageOf("eis", ref("EI"))),
+			},
+		},
+		"/units/{name}": {
+			"put": {
+				Summary:    "Change the short name, the dimension and the conversion of a unit",
+				Tags:       []string{"units"},
+				Parameters: []*openapi.Parameter{{Name: "name", In: "path", Description: "unit name", Schema: schemaOf("string"), Required: true}},
+				RequestBody: jsonBody(object(map[string]*openapi.Schema{
+					"short_name": schemaOf("string"),
+					"dimension":  schemaOf("string"),
+					"factor":     schemaOf("number"),
+					"offset":     schemaOf("number"),
+				})),
+				Responses: responses("updated", nil),
+			},
+		},
+		"/dimensions": {
+			"post": {
+				Summary:     "Create dimensions",
+				Tags:        []string{"units"},
+				RequestBody: jsonBody(object(map[string]*openapi.Schema{"dimensions": arrayOf(schemaOf("string"))})),
+				Responses:   idsResponse("ids"),
+			},
+			"get": {
+				Summary:   "List dimensions with their units",
+				Tags:      []string{"units"},
+				Responses: responses("dimensions", object(map[string]*openapi.Schema{"dimensions": arrayOf(ref("Dimension"))})),
+			},
+		},
+		"/value-types": {
+			"post": {
+				Summary:     "Create value types",
+				Tags:        []string{"value types"},
+				RequestBody: jsonBody(object(map[string]*openapi.Schema{"value_types": arrayOf(ref("ValueType"))})),
+				Responses:   responses("created", nil),
+			},
+			"get": {
+				Summary:    "List value types",
+				Tags:       []string{"value types"},
+				Parameters: pageParams("id, name"),
+				Responses:  responses("value types", pageOf("value_types", ref("ValueType"))),
+			},
+		},
+		"/value-types/{name}/values": {
+			"post": {
+				Summary:     "Add values to an enum value type",
+				Tags:        []string{"value types"},
+				Parameters:  []*openapi.Parameter{valueTypeName},
+				RequestBody: jsonBody(object(map[string]*openapi.Schema{"values": arrayOf(schemaOf("string"))}, "values")),
+				Responses:   responses("added", nil),
+			},
+		},
+		"/value-types/{name}/values/{value}": {
+			"put": {
+				Summary:     "Rename a value of an enum value type",
+				Tags:        []string{"value types"},
+				Parameters:  []*openapi.Parameter{valueTypeName, enumValue},
+				RequestBody: jsonBody(object(map[string]*openapi.Schema{"new_value": schemaOf("string")}, "new_value")),
+				Responses:   responses("renamed", nil),
+			},
+			"delete": {
+				Summary:    "Retire a value of an enum value type",
+				Tags:       []string{"value types"},
+				Parameters: []*openapi.Parameter{valueTypeName, enumValue},
+				Responses:  responses("retired", nil),
+			},
+		},
+		"/params": {
+			"post": {
+				Summary:     "Create params of the dictionary",
+				Tags:        []string{"params"},
+				RequestBody: jsonBody(object(map[string]*openapi.Schema{"params": arrayOf(ref("Param"))})),
+				Responses:   idsResponse("ids"),
+			},
+			"get": {
+				Summary:    "List params of the dictionary",
+				Tags:       []string{"params"},
+				Parameters: []*openapi.Parameter{query("param_name", "filter by the param name", schemaOf("string"), false)},
+				Responses:  responses("params", object(map[string]*openapi.Schema{"params": arrayOf(ref("Param"))})),
+			},
+		},
+		"/params/{id}/classes": {
+			"get": {
+				Summary:    "List classes that use the param",
+				Tags:       []string{"params"},
+				Parameters: []*openapi.Parameter{pathId("param id")},
+				Responses:  responses("classes", object(map[string]*openapi.Schema{"classes": arrayOf(ref("Class"))})),
+			},
+		},
+		"/classes": {
+			"post": {
+				Summary:     "Create classes",
+				Tags:        []string{"classes"},
+				Parameters:  []*openapi.Parameter{modeParam()},
+				RequestBody: jsonBody(object(map[string]*openapi.Schema{"classes": arrayOf(ref("Class"))})),
+				Responses:   bulkResponses("created", nil),
+			},
+			"get": {
+				Summary:   "Read the whole class tree",
+				Tags:      []string{"classes"},
+				Responses: responses("root classes", object(map[string]*openapi.Schema{"classes": arrayOf(ref("Class"))})),
+			},
+		},
+		"/classes/{id}": {
+			"get": {
+				Summary: "Read a class",
+				Tags:    []string{"classes"},
+				Parameters: []*openapi.Parameter{
+					classId,
+					query("all_params", "include the params inherited from the ancestors", schemaOf("boolean"), false),
+				},
+				Responses: withETag(responses("class", object(map[string]*openapi.Schema{"class": ref("Class")}))),
+			},
+			"put": {
+				Summary:     "Update a class in place",
+				Tags:        []string{"classes"},
+				Parameters:  []*openapi.Parameter{classId, ifMatch()},
+				RequestBody: jsonBody(ref("ClassUpdate")),
+				Responses:   responses("updated", nil),
+			},
+			"delete": {
+				Summary:    "Delete a class with its subtree",
+				Tags:       []string{"classes"},
+				Parameters: []*openapi.Parameter{classId, ifMatch()},
+				Responses:  responses("deleted", nil),
+			},
+		},
+		"/classes/{id}/children": {
+			"get": {
+				Summary:    "Read a class with its subtree",
+				Tags:       []string{"classes"},
+				Parameters: []*openapi.Parameter{classId},
+				Responses:  responses("class", object(map[string]*openapi.Schema{"class": ref("Class")})),
+			},
+		},
+		"/classes/{id}/move": {
+			"post": {
+				Summary:     "Move a class under another parent",
+				Description: "Responds with 409 and the conflicting product values unless force is set, which drops them. A required param can't be left without a value",
+				Tags:        []string{"classes"},
+				Parameters:  []*openapi.Parameter{classId},
+				RequestBody: jsonBody(object(map[string]*openapi.Schema{
+					"parent_id": nonNegative("integer"),
+					"force":     schemaOf("boolean"),
+				})),
+				Responses: responses("move report", ref("MoveReport")),
+			},
+		},
+		"/classes/{id}/products": {
+			"get": {
+				Summary:    "List products of a class",
+				Tags:       []string{"products"},
+				Parameters: append([]*openapi.Parameter{classId, unitParam}, pageParams("id, name, param:<param name>")...),
+				Responses:  responses("products", pageOf("products", ref("Product"))),
+			},
+		},
+		"/classes/{id}/products/search": {
+			"get": {
+				Summary: "Search products of a class subtree by param values",
+				Tags:    []string{"products"},
+				Parameters: append([]*openapi.Parameter{
+					classId,
+					query("filter", "param:op:value, op is one of eq, ne, gt, gte, lt, lte, in; "+
+						"in takes comma separated values, the value may have colons", arrayOf(schemaOf("string")), false),
+					query("facets", "comma separated params to count the values of", schemaOf("string"), false),
+				}, pageParams("id, name")...),
+				Responses: responses("products and facets", object(map[string]*openapi.Schema{
+					"products": arrayOf(ref("Product")),
+					"total":    schemaOf("integer"),
+					"limit":    schemaOf("integer"),
+					"offset":   schemaOf("integer"),
+					"facets":   arrayOf(ref("Facet")),
+				})),
+			},
+		},
+		"/classes/{id}/products/import": {
+			"post": {
+				Summary: "Import products of a terminal class from a csv table",
+				Description: "The header has the name column and the params of the class, a param column may give " +
+					"the unit of its values as \"param [unit]\". Empty cells are left without a value. " +
+					"Responds with 422 and the report when the rows are not saved",
+				Tags: []string{"products"},
+				Parameters: []*openapi.Parameter{
+					classId,
+					modeParam(),
+					query("delimiter", "a character or comma, semicolon, tab; a comma by default", schemaOf("string"), false),
+				},
+				RequestBody: &openapi.RequestBody{
+					Required: true,
+					Content:  map[string]*openapi.MediaType{"text/csv": {Schema: schemaOf("string")}},
+				},
+				Responses: responses("report of the rows", ref("ImportReport")),
+			},
+		},
+		"/classes/{id}/products/export": {
+			"get": {
+				Summary: "Export products of a class as a csv or an xlsx table",
+				Description: "A row per product and a column per param, the headers give the units of the values. " +
+					"The export of a subtree has the class column",
+				Tags: []string{"products"},
+				Parameters: []*openapi.Parameter{
+					classId,
+					query("format", "", &openapi.Schema{Type: "string", Enum: []string{formatCsv, formatXlsx}}, false),
+					query("subtree", "include the products of the descendants", schemaOf("boolean"), false),
+					query("delimiter", "a character or comma, semicolon, tab for csv; a comma by default", schemaOf("string"), false),
+				},
+				Responses: map[string]*openapi.Response{
+					"200": {
+						Description: "table",
+						Content: map[string]*openapi.MediaType{
+							"text/csv":       {Schema: schemaOf("string")},
+							xlsx.ContentType: {Schema: &openapi.Schema{Type: "string", Format: "binary"}},
+						},
+					},
+					"default": errorResponse(),
+				},
+			},
+		},
+		"/classifications/import": {
+			"post": {
+				Summary: "Import a release of a classification standard",
+				Description: "A BMEcat 2005 catalog with the classification system of an ETIM or eCl@ss release, " +
+					"or the same release in the CLASSIFICATION layout. " +
+					"The items are matched by the codes of the system and then by the names, " +
+					"so the next release updates the classes the previous one made",
+				Tags: []string{"classes"},
+				Parameters: []*openapi.Parameter{
+					query("unit", "ei of the classes and of the params without a unit, "+database.DefaultClassificationUnit+" by default",
+						schemaOf("string"), false),
+				},
+				RequestBody: &openapi.RequestBody{
+					Required: true,
+					Content:  map[string]*openapi.MediaType{"application/xml": {Schema: schemaOf("string")}},
+				},
+				Responses: responses("items created and updated by the release", ref("ClassificationReport")),
+			},
+		},
+		"/products": {
+			"post": {
+				Summary:     "Create products",
+				Tags:        []string{"products"},
+				Parameters:  []*openapi.Parameter{modeParam()},
+				RequestBody: jsonBody(object(map[string]*openapi.Schema{"products": arrayOf(ref("Product"))})),
+				Responses:   bulkResponses("created", nil),
+			},
+		},
+		"/products/{id}": {
+			"get": {
+				Summary:    "Read a product",
+				Tags:       []string{"products"},
+				Parameters: []*openapi.Parameter{productId, unitParam},
+				Responses:  withETag(responses("product", object(map[string]*openapi.Schema{"product": ref("Product")}))),
+			},
+			"put": {
+				Summary:     "Replace a product",
+				Tags:        []string{"products"},
+				Parameters:  []*openapi.Parameter{productId, ifMatch()},
+				RequestBody: jsonBody(object(map[string]*openapi.Schema{"product": ref("Product")}, "product")),
+				Responses:   responses("updated", nil),
+			},
+			"patch": {
+				Summary:     "Change the name and the listed params of a product",
+				Description: "A null value removes the value of the param",
+				Tags:        []string{"products"},
+				Parameters:  []*openapi.Parameter{productId, ifMatch()},
+				RequestBody: jsonBody(ref("Product")),
+				Responses:   responses("updated", nil),
+			},
+			"delete": {
+				Summary:    "Delete a product",
+				Tags:       []string{"products"},
+				Parameters: []*openapi.Parameter{productId, ifMatch()},
+				Responses:  responses("deleted", nil),
+			},
+		},
+		"/search": {
+			"get": {
+				Summary: "Full text search over class, product and param names and string and enum values",
+				Tags:    []string{"search"},
+				Parameters: []*openapi.Parameter{
+					query("q", "text to look for", schemaOf("string"), true),
+					query("limit", "max number of hits, 20 by default", nonNegative("integer"), false),
+				},
+				Responses: responses("hits", object(map[string]*openapi.Schema{"hits": arrayOf(ref("SearchHit"))})),
+			},
+		},
+		"/graphql": {
+			"post": {
+				Summary:     "GraphQL query over classes, products, params and units",
+				Description: "Nested fields of the classes of one level are loaded together",
+				Tags:        []string{"graphql"},
+				RequestBody: jsonBody(object(map[string]*openapi.Schema{
+					"query":         schemaOf("string"),
+					"operationName": nullable(schemaOf("string")),
+					"variables":     nullable(schemaOf("object")),
+				}, "query")),
+				Responses: map[string]*openapi.Response{
+					"200": jsonResponse("data and errors of the query", schemaOf("object")),
+				},
+			},
+		},
+	}
+}
+
+// adminPaths describes the routes that work with the whole catalog
+func adminPaths() map[string]*openapi.PathItem {
+	return map[string]*openapi.PathItem{
+		"/admin/export": {
+			"get": {
+				Summary: "Export the whole catalog",
+				Description: "Units, value types, params, the class tree and the products with their values " +
+					"in one versioned document, the items refer to each other by the names",
+				Tags:      []string{"admin"},
+				Responses: responses("dump", ref("Dump")),
+			},
+		},
+		"/admin/import": {
+			"post": {
+				Summary: "Restore the catalog from a dump",
+				Description: "The dump is restored in one transaction. The items are matched by the names: " +
+					"the existing ones are kept, the missing ones are created and the products get the values of the dump",
+				Tags:        []string{"admin"},
+				RequestBody: jsonBody(ref("Dump")),
+				Responses:   responses("ids of the dump mapped to the ids in the database", ref("RestoreReport")),
+			},
+		},
+	}
+}
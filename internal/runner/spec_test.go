@@ -0,0 +1,81 @@
+package runner
+
+import (
+	"github.com/go-chi/chi"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// undocumented are the routes that serve the specification itself and its docs
+var undocumented = map[string]bool{
+	"GET /openapi.json":  true,
+	"GET /docs":          true,
+	"GET /docs/assets/*": true,
+}
+
+func TestRoutesMatchSpec(t *testing.T) {
+	r := &Runner{spec: apiSpec()}
+	r.AddRouter()
+	routed := make(map[string]bool)
+	walk := func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		// chi keeps the wildcard of the mounted subrouters in the route
+		key := method + " " + strings.Replace(strings.TrimSuffix(route, "/"), "/*/", "/", -1)
+		if !undocumented[key] {
+			routed[key] = true
+		}
+		return nil
+	}
+	if err := chi.Walk(r.router, walk); err != nil {
+		t.Fatal(err)
+	}
+	documented := make(map[string]bool)
+	for path, item := range r.spec.Paths {
+		for method := range *item {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+	for _, key := range sortedKeys(routed) {
+		if !documented[key] {
+			t.Errorf("route %s is not in the specification", key)
+		}
+	}
+	for _, key := range sortedKeys(documented) {
+		if !routed[key] {
+			t.Errorf("specification has %s that is not routed", key)
+		}
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestDocsAssets(t *testing.T) {
+	r := &Runner{spec: apiSpec()}
+	r.AddRouter()
+	for _, path := range []string{"/docs", "/docs/assets/swagger-ui.css", "/docs/assets/swagger-ui-bundle.js"} {
+		w := httptest.NewRecorder()
+		r.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("GET %s: status %d", path, w.Code)
+		}
+	}
+}
+
+func TestAllParamsRequiredOnLegacyRoute(t *testing.T) {
+	r := &Runner{spec: apiSpec()}
+	r.AddRouter()
+	w := httptest.NewRecorder()
+	r.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/class?class_id=1", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("GET /class without all_params: status %d", w.Code)
+	}
+}
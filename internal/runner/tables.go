@@ -0,0 +1,221 @@
+package runner
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"hseSQL/internal/xlsx"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// formats of the exported tables
+const (
+	formatCsv  = "csv"
+	formatXlsx = "xlsx"
+)
+
+// delimiters can be given by the name, as ; separates the query params for some clients
+var delimiters = map[string]rune{
+	"":          ',',
+	"comma":     ',',
+	"semicolon": ';',
+	"tab":       '\t',
+}
+
+// readDelimiter reads the delimiter of the csv cells, spreadsheets of some locales use ; instead of ,
+func readDelimiter(req *http.Request) (rune, error) {
+	d := req.URL.Query().Get("delimiter")
+	if r, ok := delimiters[d]; ok {
+		return r, nil
+	}
+	r, size := utf8.DecodeRuneInString(d)
+	if size != len(d) || r == '"' || r == '\r' || r == '\n' || r == utf8.RuneError {
+		return 0, errors.New("delimiter has to be a single character")
+	}
+	return r, nil
+}
+
+// readCsv reads the header and the rows of the table, the rows may be shorter than the header
+func readCsv(body io.Reader, delimiter rune) (header []string, rows [][]string, err error) {
+	reader := csv.NewReader(body)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	header, err = reader.Read()
+	if err == io.EOF {
+		return nil, nil, errors.New("the table has no header")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if rows, err = reader.ReadAll(); err != nil {
+		return nil, nil, err
+	}
+	for i := range header {
+		header[i] = csvUnquote(header[i])
+	}
+	for _, row := range rows {
+		for i := range row {
+			row[i] = csvUnquote(row[i])
+		}
+	}
+	return header, rows, nil
+}
+
+// ImportPC creates the products of the class from a csv table with the name column and a column
+// for every param. The report has a result for every row, it comes with 422 if nothing was saved
+func (r *Runner) ImportPC(w http.ResponseWriter, req *http.Request) {
+	id, err := readId(req, "")
+	if err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	partial, err := readMode(req)
+	if err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	delimiter, err := readDelimiter(req)
+	if err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	header, rows, err := readCsv(req.Body, delimiter)
+	if err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	report, err := r.do.ImportClassProducts(id, header, rows, partial)
+	if err != nil {
+		log.Error(err)
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if !report.Imported {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Error(err)
+		return
+	}
+	return
+}
+
+// ExportPC responds with the products of the class, or of its subtree with subtree=true,
+// as a csv or an xlsx table with a row per product and a column per param
+func (r *Runner) ExportPC(w http.ResponseWriter, req *http.Request) {
+	id, err := readId(req, "")
+	if err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	q := req.URL.Query()
+	format := q.Get("format")
+	if format == "" {
+		format = formatCsv
+	}
+	if format != formatCsv && format != formatXlsx {
+		writeError(w, newRequestErr(fmt.Errorf("unknown format %s", format)))
+		return
+	}
+	var subtree bool
+	if s := q.Get("subtree"); s != "" {
+		if subtree, err = strconv.ParseBool(s); err != nil {
+			log.Error(err)
+			writeError(w, newRequestErr(err))
+			return
+		}
+	}
+	delimiter, err := readDelimiter(req)
+	if err != nil {
+		log.Error(err)
+		writeError(w, newRequestErr(err))
+		return
+	}
+	table, err := r.do.ExportClassProducts(id, subtree)
+	if err != nil {
+		log.Error(err)
+		writeError(w, err)
+		return
+	}
+	filename := strconv.Quote(fmt.Sprintf("class-%d.%s", id, format))
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	if format == formatXlsx {
+		w.Header().Set("Content-Type", xlsx.ContentType)
+		if err := xlsx.Write(w, table.Class, table.Header, table.Rows); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	cw := csv.NewWriter(w)
+	cw.Comma = delimiter
+	header := make([]string, len(table.Header))
+	for i, h := range table.Header {
+		header[i] = csvText(h)
+	}
+	if err := cw.Write(header); err != nil {
+		log.Error(err)
+		return
+	}
+	record := make([]string, len(table.Header))
+	for _, row := range table.Rows {
+		for i, cell := range row {
+			record[i] = csvCell(cell)
+		}
+		if err := cw.Write(record); err != nil {
+			log.Error(err)
+			return
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		log.Error(err)
+	}
+}
+
+// csvCell writes the value the way the import reads it back
+func csvCell(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case json.Number:
+		return v.String()
+	case string:
+		return csvText(v)
+	}
+	return fmt.Sprint(v)
+}
+
+// formulaStart are the characters a spreadsheet starts a formula with
+const formulaStart = "=+-@\t\r"
+
+// csvText prefixes the text a spreadsheet would take for a formula with ', which the spreadsheets
+// hide and the import drops, so a product name can't run a formula in the analysts' spreadsheets
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune(formulaStart, rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// csvUnquote drops the ' csvText puts before a formula
+func csvUnquote(s string) string {
+	if len(s) > 1 && s[0] == '\'' && strings.ContainsRune(formulaStart, rune(s[1])) {
+		return s[1:]
+	}
+	return s
+}
@@ -0,0 +1,45 @@
+package runner
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCsvCell(t *testing.T) {
+	tests := []struct {
+		v    interface{}
+		want string
+	}{
+		{"Cable", "Cable"},
+		{"=HYPERLINK(\"http://x\")", "'=HYPERLINK(\"http://x\")"},
+		{"+1", "'+1"},
+		{"-cmd", "'-cmd"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\tTab", "'\tTab"},
+		{"a=b", "a=b"},
+		{"", ""},
+		{int64(-5), "-5"},
+		{json.Number("-0.5"), "-0.5"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := csvCell(tt.v); got != tt.want {
+			t.Errorf("csvCell(%#v) = %q, want %q", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestReadCsvUnquotesFormulas(t *testing.T) {
+	header, rows, err := readCsv(strings.NewReader("name,'=note\n'=SUM(A1),'x\n"), ',')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"name", "=note"}; !reflect.DeepEqual(header, want) {
+		t.Errorf("header %q, want %q", header, want)
+	}
+	if want := [][]string{{"=SUM(A1)", "'x"}}; !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows %q, want %q", rows, want)
+	}
+}
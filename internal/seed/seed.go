@@ -0,0 +1,185 @@
+// Package seed reads the yaml files that describe a catalog to start the development or the tests with.
+// A seed is restored like a dump of the catalog, so the items that already exist are kept
+// and applying the same file again changes nothing. An item the file defines differently
+// from the database, like a changed constraint, is reported as a conflict and nothing is applied
+package seed
+
+import (
+	"gopkg.in/yaml.v2"
+	"hseSQL/internal"
+	"hseSQL/internal/database"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+type File struct {
+	Dimensions []string     `yaml:"dimensions"`
+	Eis        []*Ei        `yaml:"eis"`
+	ValueTypes []*ValueType `yaml:"value_types"`
+	Classes    []*Class     `yaml:"classes"`
+	Products   []*Product   `yaml:"products"`
+}
+
+type Ei struct {
+	Name      string  `yaml:"name"`
+	ShortName string  `yaml:"short_name"`
+	Dimension string  `yaml:"dimension"`
+	Factor    float64 `yaml:"factor"`
+	Offset    float64 `yaml:"offset"`
+}
+
+type ValueType struct {
+	Name     string   `yaml:"name"`
+	BaseType string   `yaml:"base_type"`
+	Values   []string `yaml:"values"`
+}
+
+type Constraints struct {
+	Min       *float64 `yaml:"min"`
+	Max       *float64 `yaml:"max"`
+	Step      *float64 `yaml:"step"`
+	Precision *int     `yaml:"precision"`
+	MaxLength *int     `yaml:"max_length"`
+}
+
+// Param is defined by the class it is listed in, ValType and Ei are
+// only needed for a param that is not in the catalog yet
+type Param struct {
+	Name        string       `yaml:"name"`
+	ValType     string       `yaml:"val_type"`
+	Ei          string       `yaml:"ei"`
+	Constraints *Constraints `yaml:"constraints"`
+	Required    bool         `yaml:"required"`
+	Default     interface{}  `yaml:"default"`
+}
+
+type Override struct {
+	Name        string       `yaml:"name"`
+	Constraints *Constraints `yaml:"constraints"`
+	Required    *bool        `yaml:"required"`
+	Default     interface{}  `yaml:"default"`
+	DisplayEi   string       `yaml:"display_ei"`
+}
+
+type Class struct {
+	Name      string      `yaml:"name"`
+	Ei        string      `yaml:"ei"`
+	Params    []*Param    `yaml:"params"`
+	Overrides []*Override `yaml:"overrides"`
+	Children  []*Class    `yaml:"children"`
+}
+
+// Product has the values by the param names in the eis of the params
+type Product struct {
+	Name   string                 `yaml:"name"`
+	Class  string                 `yaml:"class"`
+	Values map[string]interface{} `yaml:"values"`
+}
+
+// ReadFile reads the seed, the unknown keys are rejected so a typo doesn't go unnoticed
+func ReadFile(path string) (*File, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	f := &File{}
+	if err := yaml.UnmarshalStrict(data, f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Dump turns the seed into the dump of the catalog it describes
+func (f *File) Dump() *database.Dump {
+	d := &database.Dump{
+		Format:     database.DumpFormat,
+		Version:    database.DumpVersion,
+		Created:    time.Now().UTC(),
+		Dimensions: f.Dimensions,
+	}
+	for _, ei := range f.Eis {
+		d.EIs = append(d.EIs, &internal.EI{
+			Name:      ei.Name,
+			ShortName: ei.ShortName,
+			Dimension: ei.Dimension,
+			Factor:    ei.Factor,
+			Offset:    ei.Offset,
+		})
+	}
+	for _, vt := range f.ValueTypes {
+		valueType := &internal.ValueType{Name: vt.Name, BaseType: vt.BaseType}
+		for _, v := range vt.Values {
+			valueType.Values = append(valueType.Values, &internal.EnumValue{Value: v})
+		}
+		d.ValueTypes = append(d.ValueTypes, valueType)
+	}
+	for _, c := range f.Classes {
+		d.Classes = append(d.Classes, c.class())
+	}
+	for _, p := range f.Products {
+		d.Products = append(d.Products, &database.DumpProduct{
+			Name:   p.Name,
+			Class:  p.Class,
+			Values: p.Values,
+		})
+	}
+	return d
+}
+
+func (c *Class) class() *internal.Class {
+	class := &internal.Class{
+		Name:     c.Name,
+		Ei:       ei(c.Ei),
+		Params:   []*internal.Param{},
+		Children: []*internal.Class{},
+	}
+	for _, p := range c.Params {
+		class.Params = append(class.Params, &internal.Param{
+			Name:        p.Name,
+			ValType:     p.ValType,
+			EI:          ei(p.Ei),
+			Constraints: p.Constraints.constraints(),
+			Required:    p.Required,
+			Default:     p.Default,
+		})
+	}
+	for _, o := range c.Overrides {
+		class.Overrides = append(class.Overrides, &internal.ParamOverride{
+			Name:        o.Name,
+			Constraints: o.Constraints.constraints(),
+			Required:    o.Required,
+			Default:     o.Default,
+			DisplayEI:   ei(o.DisplayEi),
+		})
+	}
+	for _, child := range c.Children {
+		class.Children = append(class.Children, child.class())
+	}
+	return class
+}
+
+func (c *Constraints) constraints() *internal.ParamConstraints {
+	if c == nil {
+		return nil
+	}
+	return &internal.ParamConstraints{
+		Min:       c.Min,
+		Max:       c.Max,
+		Step:      c.Step,
+		Precision: c.Precision,
+		MaxLength: c.MaxLength,
+	}
+}
+
+func ei(name string) *internal.EI {
+	if name == "" {
+		return nil
+	}
+	return &internal.EI{Name: name}
+}
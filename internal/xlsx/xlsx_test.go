@@ -0,0 +1,156 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestColumn(t *testing.T) {
+	tests := []struct {
+		i    int
+		want string
+	}{
+		{0, "A"},
+		{1, "B"},
+		{25, "Z"},
+		{26, "AA"},
+		{27, "AB"},
+		{51, "AZ"},
+		{52, "BA"},
+		{701, "ZZ"},
+		{702, "AAA"},
+		{16383, "XFD"},
+	}
+	for _, tt := range tests {
+		if got := column(tt.i); got != tt.want {
+			t.Errorf("column(%d) = %q, want %q", tt.i, got, tt.want)
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		s, want string
+	}{
+		{"plain", "plain"},
+		{"a < b & c > d", "a &lt; b &amp; c &gt; d"},
+		{`"quoted" 'single'`, "&#34;quoted&#34; &#39;single&#39;"},
+		{"line\nbreak", "line&#xA;break"},
+		{"кабель", "кабель"},
+	}
+	for _, tt := range tests {
+		if got := escape(tt.s); got != tt.want {
+			t.Errorf("escape(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestSheetName(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"Cables", "Cables"},
+		{"Power [cables]: 1/2", "Power cables 12"},
+		{`a*b?c\d`, "abcd"},
+		{"", "Sheet1"},
+		{"[]:*?/\\", "Sheet1"},
+		{"   ", "Sheet1"},
+		{strings.Repeat("x", 40), strings.Repeat("x", maxSheetName)},
+		{strings.Repeat("я", 40), strings.Repeat("я", maxSheetName)},
+	}
+	for _, tt := range tests {
+		if got := sheetName(tt.name); got != tt.want {
+			t.Errorf("sheetName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	rows := [][]interface{}{
+		{"cable <1>", int64(3), json.Number("0.1"), true, nil},
+		{"a & b", 2, 1.5, false, "last"},
+	}
+	if err := Write(&buf, "Power: cables", []string{"name", "cores", "section", "armoured", "note"}, rows); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts[f.Name] = string(data)
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/_rels/workbook.xml.rels",
+		"xl/workbook.xml", "xl/worksheets/sheet1.xml"} {
+		part, ok := parts[name]
+		if !ok {
+			t.Errorf("part %s is missing", name)
+			continue
+		}
+		if err := xml.Unmarshal([]byte(part), new(interface{})); err != nil {
+			t.Errorf("part %s is not well formed: %v", name, err)
+		}
+	}
+	if !strings.Contains(parts["xl/workbook.xml"], `name="Power cables"`) {
+		t.Errorf("workbook doesn't name the sheet: %s", parts["xl/workbook.xml"])
+	}
+	type cell struct {
+		Ref    string `xml:"r,attr"`
+		Type   string `xml:"t,attr"`
+		Value  string `xml:"v"`
+		Inline string `xml:"is>t"`
+	}
+	var sheet struct {
+		Rows []struct {
+			N     int    `xml:"r,attr"`
+			Cells []cell `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := xml.Unmarshal([]byte(parts["xl/worksheets/sheet1.xml"]), &sheet); err != nil {
+		t.Fatal(err)
+	}
+	if len(sheet.Rows) != 3 {
+		t.Fatalf("sheet has %d rows, want 3", len(sheet.Rows))
+	}
+	want := []cell{
+		{Ref: "A2", Type: "inlineStr", Inline: "cable <1>"},
+		{Ref: "B2", Value: "3"},
+		{Ref: "C2", Value: "0.1"},
+		{Ref: "D2", Type: "b", Value: "1"},
+	}
+	got := sheet.Rows[1].Cells
+	if len(got) != len(want) {
+		t.Fatalf("row 2 has %d cells, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("cell %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if last := sheet.Rows[2].Cells; len(last) != 5 || last[0].Inline != "a & b" || last[4].Ref != "E3" {
+		t.Errorf("row 3 = %+v", last)
+	}
+}
+
+func TestWriteRejectsUnknownCell(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, "s", []string{"a"}, [][]interface{}{{struct{}{}}}); err == nil {
+		t.Error("Write accepted a struct cell")
+	}
+}